@@ -0,0 +1,76 @@
+package gopsd
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+const defaultPort = "2947"
+
+// Network endpoint resolved from a Dial address
+type endpoint struct {
+	network string // tcp, tcp4, tcp6 or unix
+	address string // host:port or socket path
+	device  string // WATCH device restriction, empty for all devices
+}
+
+// Parse a Dial address. Accepted forms are:
+//
+//	host:port                  TCP over IPv4 or IPv6
+//	/path/to/socket            Unix domain socket
+//	tcp://host:port            also tcp4:// and tcp6://
+//	unix:///path/to/socket
+//	gpsd://host:port/dev/tty   TCP, watching only the given device
+//
+// The port defaults to 2947 when omitted from a URL. A Unix socket must
+// speak the gpsd client protocol, e.g. one proxied into a container.
+func parseAddress(address string) (endpoint, error) {
+	if address == "" {
+		address = DefaultAddress
+	}
+	if strings.HasPrefix(address, "/") {
+		return endpoint{network: "unix", address: address}, nil
+	}
+	if !strings.Contains(address, "://") {
+		return endpoint{network: "tcp", address: address}, nil
+	}
+
+	u, err := url.Parse(address)
+	if err != nil {
+		return endpoint{}, fmt.Errorf("invalid GPSD address %q: %w", address, err)
+	}
+
+	switch u.Scheme {
+	case "tcp", "tcp4", "tcp6":
+		if u.Path != "" && u.Path != "/" {
+			return endpoint{}, fmt.Errorf("invalid GPSD address %q: unexpected path", address)
+		}
+		return endpoint{network: u.Scheme, address: withPort(u.Host)}, nil
+	case "unix":
+		path := u.Host + u.Path
+		if path == "" {
+			return endpoint{}, fmt.Errorf("invalid GPSD address %q: missing socket path", address)
+		}
+		return endpoint{network: "unix", address: path}, nil
+	case "gpsd":
+		device := u.Path
+		if device == "/" {
+			device = ""
+		}
+		return endpoint{network: "tcp", address: withPort(u.Host), device: device}, nil
+	}
+	return endpoint{}, fmt.Errorf("invalid GPSD address %q: unsupported scheme %q", address, u.Scheme)
+}
+
+// Add the default GPSD port to a host lacking one
+func withPort(host string) string {
+	if host == "" {
+		return DefaultAddress
+	}
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	return net.JoinHostPort(strings.Trim(host, "[]"), defaultPort)
+}
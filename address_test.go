@@ -0,0 +1,39 @@
+package gopsd
+
+import "testing"
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		want    endpoint
+		ok      bool
+	}{
+		{"empty", "", endpoint{"tcp", DefaultAddress, ""}, true},
+		{"host and port", "gps.local:2948", endpoint{"tcp", "gps.local:2948", ""}, true},
+		{"bare path", "/run/gpsd.sock", endpoint{"unix", "/run/gpsd.sock", ""}, true},
+		{"unix URL", "unix:///run/gpsd.sock", endpoint{"unix", "/run/gpsd.sock", ""}, true},
+		{"unix URL without path", "unix://", endpoint{}, false},
+		{"tcp URL", "tcp://gps.local:2948", endpoint{"tcp", "gps.local:2948", ""}, true},
+		{"tcp URL default port", "tcp://gps.local", endpoint{"tcp", "gps.local:2947", ""}, true},
+		{"tcp URL trailing slash", "tcp://gps.local/", endpoint{"tcp", "gps.local:2947", ""}, true},
+		{"tcp URL without host", "tcp://", endpoint{"tcp", DefaultAddress, ""}, true},
+		{"tcp URL with path", "tcp://gps.local/dev/ttyACM0", endpoint{}, false},
+		{"tcp4 URL", "tcp4://127.0.0.1", endpoint{"tcp4", "127.0.0.1:2947", ""}, true},
+		{"tcp6 URL default port", "tcp6://[::1]", endpoint{"tcp6", "[::1]:2947", ""}, true},
+		{"tcp6 URL with port", "tcp6://[::1]:2948", endpoint{"tcp6", "[::1]:2948", ""}, true},
+		{"gpsd URL with device", "gpsd://gps.local/dev/tty", endpoint{"tcp", "gps.local:2947", "/dev/tty"}, true},
+		{"gpsd URL with port and device", "gpsd://gps.local:2948/dev/ttyUSB0", endpoint{"tcp", "gps.local:2948", "/dev/ttyUSB0"}, true},
+		{"gpsd URL without device", "gpsd://gps.local/", endpoint{"tcp", "gps.local:2947", ""}, true},
+		{"unsupported scheme", "http://gps.local", endpoint{}, false},
+		{"malformed URL", "tcp://[::1", endpoint{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAddress(tt.address)
+			if (err == nil) != tt.ok || got != tt.want {
+				t.Errorf("parseAddress(%q) = %+v, %v, want %+v, ok %v", tt.address, got, err, tt.want, tt.ok)
+			}
+		})
+	}
+}
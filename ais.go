@@ -0,0 +1,359 @@
+/*
+* ais.go
+*
+* AIS reports derived from https://gpsd.gitlab.io/gpsd/AIVDM.html
+*
+ */
+
+package gopsd
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+)
+
+// AIS header shared by every message type, embedded in the typed reports
+type AIS struct {
+	Class  string `json:"class"`  // Fixed: "AIS"
+	Device string `json:"device"` // Name of the originating device
+	Type   int    `json:"type"`   // Message type (1-27)
+	Repeat int    `json:"repeat"` // Repeat indicator
+	MMSI   int    `json:"mmsi"`   // Maritime Mobile Service Identity
+	Scaled bool   `json:"scaled"` // Values are in natural units, set by WATCH scaled
+}
+
+// Types 1, 2 and 3: Class A position report
+type AISPosition struct {
+	AIS
+	Status     int             `json:"status"`                // Navigation status
+	StatusText string          `json:"status_text,omitempty"` // Navigation status text (scaled)
+	Turn       json.RawMessage `json:"turn"`                  // Rate of turn, see RateOfTurn
+	Speed      float64         `json:"speed"`                 // Speed over ground, see SpeedKnots
+	Accuracy   bool            `json:"accuracy"`              // Position accuracy better than 10 m
+	Lon        float64         `json:"lon"`                   // Longitude, see Position
+	Lat        float64         `json:"lat"`                   // Latitude, see Position
+	Course     float64         `json:"course"`                // Course over ground, see CourseDegrees
+	Heading    int             `json:"heading"`               // True heading in degrees, 511 if not available
+	Second     int             `json:"second"`                // UTC second of the report
+	Maneuver   int             `json:"maneuver"`              // Special maneuver indicator
+	RAIM       bool            `json:"raim"`                  // RAIM flag
+	Radio      int             `json:"radio"`                 // Radio status
+}
+
+// Types 4 and 11: base station report and UTC/date response
+type AISBaseStation struct {
+	AIS
+	Timestamp string  `json:"timestamp,omitempty"` // ISO8601 time (scaled)
+	Year      int     `json:"year,omitempty"`      // UTC year (unscaled)
+	Month     int     `json:"month,omitempty"`     // UTC month (unscaled)
+	Day       int     `json:"day,omitempty"`       // UTC day (unscaled)
+	Hour      int     `json:"hour,omitempty"`      // UTC hour (unscaled)
+	Minute    int     `json:"minute,omitempty"`    // UTC minute (unscaled)
+	Second    int     `json:"second,omitempty"`    // UTC second (unscaled)
+	Accuracy  bool    `json:"accuracy"`            // Position accuracy better than 10 m
+	Lon       float64 `json:"lon"`                 // Longitude, see Position
+	Lat       float64 `json:"lat"`                 // Latitude, see Position
+	EPFD      int     `json:"epfd"`                // Type of position fixing device
+	RAIM      bool    `json:"raim"`                // RAIM flag
+	Radio     int     `json:"radio"`               // Radio status
+}
+
+// Type 5: static and voyage related data
+type AISStaticVoyage struct {
+	AIS
+	AISVersion   int     `json:"ais_version"`             // AIS version
+	IMO          int     `json:"imo"`                     // IMO ship ID number
+	Callsign     string  `json:"callsign"`                // Call sign
+	Shipname     string  `json:"shipname"`                // Vessel name
+	Shiptype     int     `json:"shiptype"`                // Ship and cargo type
+	ToBow        int     `json:"to_bow"`                  // Dimension to bow (meters)
+	ToStern      int     `json:"to_stern"`                // Dimension to stern (meters)
+	ToPort       int     `json:"to_port"`                 // Dimension to port (meters)
+	ToStarboard  int     `json:"to_starboard"`            // Dimension to starboard (meters)
+	EPFD         int     `json:"epfd"`                    // Type of position fixing device
+	ETA          string  `json:"eta,omitempty"`           // Estimated time of arrival (scaled)
+	Month        int     `json:"month,omitempty"`         // ETA month (unscaled)
+	Day          int     `json:"day,omitempty"`           // ETA day (unscaled)
+	Hour         int     `json:"hour,omitempty"`          // ETA hour (unscaled)
+	Minute       int     `json:"minute,omitempty"`        // ETA minute (unscaled)
+	Draught      float64 `json:"draught"`                 // Draught, see DraughtMeters
+	Destination  string  `json:"destination"`             // Destination
+	DTE          int     `json:"dte"`                     // Data terminal ready (0 = available)
+	ShiptypeText string  `json:"shiptype_text,omitempty"` // Ship type text (scaled)
+}
+
+// Types 6 and 8: addressed and broadcast binary messages
+type AISBinary struct {
+	AIS
+	SeqNo      int    `json:"seqno,omitempty"`     // Sequence number (type 6)
+	DestMMSI   int    `json:"dest_mmsi,omitempty"` // Destination MMSI (type 6)
+	Retransmit bool   `json:"retransmit"`          // Retransmit flag (type 6)
+	DAC        int    `json:"dac"`                 // Designated area code
+	FID        int    `json:"fid"`                 // Functional ID
+	Data       string `json:"data,omitempty"`      // Undecoded payload as bitcount:hex
+}
+
+// Types 12 and 14: addressed and broadcast safety related messages
+type AISSafety struct {
+	AIS
+	SeqNo      int    `json:"seqno,omitempty"`     // Sequence number (type 12)
+	DestMMSI   int    `json:"dest_mmsi,omitempty"` // Destination MMSI (type 12)
+	Retransmit bool   `json:"retransmit"`          // Retransmit flag (type 12)
+	Text       string `json:"text"`                // Safety message text
+}
+
+// Types 18 and 19: Class B position reports, type 19 adds static data
+type AISClassBPosition struct {
+	AIS
+	Speed       float64 `json:"speed"`                  // Speed over ground, see SpeedKnots
+	Accuracy    bool    `json:"accuracy"`               // Position accuracy better than 10 m
+	Lon         float64 `json:"lon"`                    // Longitude, see Position
+	Lat         float64 `json:"lat"`                    // Latitude, see Position
+	Course      float64 `json:"course"`                 // Course over ground, see CourseDegrees
+	Heading     int     `json:"heading"`                // True heading in degrees, 511 if not available
+	Second      int     `json:"second"`                 // UTC second of the report
+	Regional    int     `json:"regional"`               // Regional reserved bits
+	CS          bool    `json:"cs,omitempty"`           // Carrier sense unit (type 18)
+	Display     bool    `json:"display,omitempty"`      // Has display (type 18)
+	DSC         bool    `json:"dsc,omitempty"`          // Has DSC (type 18)
+	Band        bool    `json:"band,omitempty"`         // Can use whole marine band (type 18)
+	Msg22       bool    `json:"msg22,omitempty"`        // Accepts channel management (type 18)
+	Shipname    string  `json:"shipname,omitempty"`     // Vessel name (type 19)
+	Shiptype    int     `json:"shiptype,omitempty"`     // Ship and cargo type (type 19)
+	ToBow       int     `json:"to_bow,omitempty"`       // Dimension to bow (type 19, meters)
+	ToStern     int     `json:"to_stern,omitempty"`     // Dimension to stern (type 19, meters)
+	ToPort      int     `json:"to_port,omitempty"`      // Dimension to port (type 19, meters)
+	ToStarboard int     `json:"to_starboard,omitempty"` // Dimension to starboard (type 19, meters)
+	EPFD        int     `json:"epfd,omitempty"`         // Type of position fixing device (type 19)
+	Assigned    bool    `json:"assigned"`               // Assigned mode flag
+	RAIM        bool    `json:"raim"`                   // RAIM flag
+	Radio       int     `json:"radio,omitempty"`        // Radio status (type 18)
+}
+
+// Type 21: aid to navigation report
+type AISAidToNavigation struct {
+	AIS
+	AidType     int     `json:"aid_type"`     // Type of navigational aid
+	Name        string  `json:"name"`         // Name of the aid
+	Accuracy    bool    `json:"accuracy"`     // Position accuracy better than 10 m
+	Lon         float64 `json:"lon"`          // Longitude, see Position
+	Lat         float64 `json:"lat"`          // Latitude, see Position
+	ToBow       int     `json:"to_bow"`       // Dimension to bow (meters)
+	ToStern     int     `json:"to_stern"`     // Dimension to stern (meters)
+	ToPort      int     `json:"to_port"`      // Dimension to port (meters)
+	ToStarboard int     `json:"to_starboard"` // Dimension to starboard (meters)
+	EPFD        int     `json:"epfd"`         // Type of position fixing device
+	Second      int     `json:"second"`       // UTC second of the report
+	OffPosition bool    `json:"off_position"` // Aid is off its charted position
+	Regional    int     `json:"regional"`     // Regional reserved bits
+	RAIM        bool    `json:"raim"`         // RAIM flag
+	VirtualAid  bool    `json:"virtual_aid"`  // Aid is virtual
+	Assigned    bool    `json:"assigned"`     // Assigned mode flag
+}
+
+// Type 24: static data report, sent as part A (name) and part B (the rest)
+// unless WATCH split24 asks GPSD to aggregate them, see AISStaticAssembler
+type AISStaticData struct {
+	AIS
+	PartNo         int    `json:"partno"`                    // 0 for part A, 1 for part B
+	Shipname       string `json:"shipname,omitempty"`        // Vessel name (part A)
+	Shiptype       int    `json:"shiptype,omitempty"`        // Ship and cargo type (part B)
+	VendorID       string `json:"vendorid,omitempty"`        // Vendor ID (part B)
+	Model          int    `json:"model,omitempty"`           // Unit model code (part B)
+	Serial         int    `json:"serial,omitempty"`          // Serial number (part B)
+	Callsign       string `json:"callsign,omitempty"`        // Call sign (part B)
+	ToBow          int    `json:"to_bow,omitempty"`          // Dimension to bow (part B, meters)
+	ToStern        int    `json:"to_stern,omitempty"`        // Dimension to stern (part B, meters)
+	ToPort         int    `json:"to_port,omitempty"`         // Dimension to port (part B, meters)
+	ToStarboard    int    `json:"to_starboard,omitempty"`    // Dimension to starboard (part B, meters)
+	MothershipMMSI int    `json:"mothership_mmsi,omitempty"` // Mothership MMSI for auxiliary craft (part B)
+}
+
+// Any other message type, the raw report is kept for custom decoding
+type AISOther struct {
+	AIS
+	Data []byte `json:"-"` // The report as received
+}
+
+// Merges type 24 part A and part B reports per MMSI
+type AISStaticAssembler struct {
+	parts map[int]*AISStaticData // Partial reports awaiting their other half
+}
+
+func (AIS) ReportClass() string { return "AIS" }
+
+// Decode an AIS report into the struct for its message type
+func unmarshalAIS(d Decoder, data []byte) (Report, error) {
+	msgType := 0
+	eachMember(data, func(key, value []byte) bool {
+		if string(key) != "type" {
+			return true
+		}
+		msgType, _ = strconv.Atoi(string(value))
+		return false
+	})
+
+	var report Report
+	switch msgType {
+	case 1, 2, 3:
+		report = &AISPosition{}
+	case 4, 11:
+		report = &AISBaseStation{}
+	case 5:
+		report = &AISStaticVoyage{}
+	case 6, 8:
+		report = &AISBinary{}
+	case 12, 14:
+		report = &AISSafety{}
+	case 18, 19:
+		report = &AISClassBPosition{}
+	case 21:
+		report = &AISAidToNavigation{}
+	case 24:
+		report = &AISStaticData{}
+	default:
+		report = &AISOther{Data: append([]byte(nil), data...)}
+	}
+
+	if err := d.Unmarshal(data, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Latitude and longitude in degrees, ok is false when not available
+func (r *AISPosition) Position() (lat, lon float64, ok bool) {
+	return aisPosition(r.Lat, r.Lon, r.Scaled)
+}
+
+// Speed over ground in knots, ok is false when not available
+func (r *AISPosition) SpeedKnots() (float64, bool) {
+	return aisTenths(r.Speed, r.Scaled, 1023)
+}
+
+// Course over ground in degrees, ok is false when not available
+func (r *AISPosition) CourseDegrees() (float64, bool) {
+	return aisTenths(r.Course, r.Scaled, 3600)
+}
+
+// Rate of turn in degrees per minute, positive to starboard. Turns faster
+// than 5 degrees per 30 seconds without a turn indicator are reported as
+// ±720, ok is false when not available.
+func (r *AISPosition) RateOfTurn() (float64, bool) {
+	var text string
+	if err := json.Unmarshal(r.Turn, &text); err == nil {
+		switch text {
+		case "fastright":
+			return 720, true
+		case "fastleft":
+			return -720, true
+		}
+		v, err := strconv.ParseFloat(text, 64)
+		return v, err == nil && !math.IsNaN(v)
+	}
+
+	var v float64
+	if err := json.Unmarshal(r.Turn, &v); err != nil {
+		return 0, false
+	}
+	if r.Scaled {
+		return v, true
+	}
+	switch {
+	case v == -128:
+		return 0, false
+	case v == 127:
+		return 720, true
+	case v == -127:
+		return -720, true
+	}
+	rot := (v / 4.733) * (v / 4.733)
+	return math.Copysign(rot, v), true
+}
+
+// Latitude and longitude in degrees, ok is false when not available
+func (r *AISBaseStation) Position() (lat, lon float64, ok bool) {
+	return aisPosition(r.Lat, r.Lon, r.Scaled)
+}
+
+// Draught in meters
+func (r *AISStaticVoyage) DraughtMeters() float64 {
+	if r.Scaled {
+		return r.Draught
+	}
+	return r.Draught / 10
+}
+
+// Latitude and longitude in degrees, ok is false when not available
+func (r *AISClassBPosition) Position() (lat, lon float64, ok bool) {
+	return aisPosition(r.Lat, r.Lon, r.Scaled)
+}
+
+// Speed over ground in knots, ok is false when not available
+func (r *AISClassBPosition) SpeedKnots() (float64, bool) {
+	return aisTenths(r.Speed, r.Scaled, 1023)
+}
+
+// Course over ground in degrees, ok is false when not available
+func (r *AISClassBPosition) CourseDegrees() (float64, bool) {
+	return aisTenths(r.Course, r.Scaled, 3600)
+}
+
+// Latitude and longitude in degrees, ok is false when not available
+func (r *AISAidToNavigation) Position() (lat, lon float64, ok bool) {
+	return aisPosition(r.Lat, r.Lon, r.Scaled)
+}
+
+// Report whether both halves of the static data are present
+func (r *AISStaticData) Complete() bool {
+	return r.Shipname != "" && (r.Callsign != "" || r.VendorID != "" || r.Shiptype != 0)
+}
+
+// Add a type 24 report, returning the merged report once both parts of
+// a vessel have been seen. Reports GPSD already aggregated pass through.
+func (a *AISStaticAssembler) Add(r *AISStaticData) (*AISStaticData, bool) {
+	if r.Complete() {
+		return r, true
+	}
+	if a.parts == nil {
+		a.parts = map[int]*AISStaticData{}
+	}
+
+	other, ok := a.parts[r.MMSI]
+	if !ok || other.PartNo == r.PartNo {
+		a.parts[r.MMSI] = r
+		return nil, false
+	}
+	delete(a.parts, r.MMSI)
+
+	partA, partB := other, r
+	if r.PartNo == 0 {
+		partA, partB = r, other
+	}
+	merged := *partB
+	merged.Shipname = partA.Shipname
+	return &merged, true
+}
+
+// Convert an AIS coordinate pair, unscaled values are in 1/10000 minute
+func aisPosition(lat, lon float64, scaled bool) (float64, float64, bool) {
+	if !scaled {
+		lat, lon = lat/600000, lon/600000
+	}
+	if math.Abs(lat) > 90 || math.Abs(lon) > 180 {
+		return 0, 0, false
+	}
+	return lat, lon, true
+}
+
+// Convert a value transmitted in tenths, unavailable is the raw sentinel
+func aisTenths(v float64, scaled bool, unavailable float64) (float64, bool) {
+	if !scaled {
+		v /= 10
+	}
+	if v >= unavailable/10 {
+		return 0, false
+	}
+	return v, true
+}
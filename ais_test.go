@@ -0,0 +1,227 @@
+package gopsd
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// gpsd output for sample AIVDM sentences, with and without WATCH scaled
+const (
+	aisType1Unscaled = `{"class":"AIS","device":"stdin","type":1,"repeat":0,"mmsi":371798000,"scaled":false,"status":0,"turn":-127,"speed":123,"accuracy":true,"lon":-74037228,"lat":29028978,"course":2240,"heading":215,"second":33,"maneuver":0,"raim":false,"radio":34017}`
+	aisType1Scaled   = `{"class":"AIS","device":"stdin","type":1,"repeat":0,"mmsi":371798000,"scaled":true,"status":0,"status_text":"Under way using engine","turn":"fastleft","speed":12.3,"accuracy":true,"lon":-123.3954,"lat":48.3816,"course":224.0,"heading":215,"second":33,"maneuver":0,"raim":false,"radio":34017}`
+	aisType5Unscaled = `{"class":"AIS","device":"stdin","type":5,"repeat":0,"mmsi":351759000,"scaled":false,"imo":9134270,"ais_version":0,"callsign":"3FOF8","shipname":"EVER DIADEM","shiptype":70,"to_bow":225,"to_stern":70,"to_port":1,"to_starboard":31,"epfd":1,"month":5,"day":15,"hour":14,"minute":0,"draught":122,"destination":"NEW YORK","dte":0}`
+	aisType5Scaled   = `{"class":"AIS","device":"stdin","type":5,"repeat":0,"mmsi":351759000,"scaled":true,"imo":9134270,"ais_version":0,"callsign":"3FOF8","shipname":"EVER DIADEM","shiptype":70,"shiptype_text":"Cargo, all ships of this type","to_bow":225,"to_stern":70,"to_port":1,"to_starboard":31,"epfd":1,"epfd_text":"GPS","eta":"05-15T14:00Z","draught":12.2,"destination":"NEW YORK","dte":0}`
+	aisType18Missing = `{"class":"AIS","device":"stdin","type":18,"repeat":0,"mmsi":338087471,"scaled":false,"reserved":0,"speed":1023,"accuracy":false,"lon":108600000,"lat":54600000,"course":3600,"heading":511,"second":60,"regional":0,"cs":true,"display":false,"dsc":true,"band":true,"msg22":true,"raim":false,"radio":917510}`
+	aisType24PartA   = `{"class":"AIS","device":"stdin","type":24,"repeat":0,"mmsi":271041815,"scaled":true,"partno":0,"shipname":"PROGUY"}`
+	aisType24PartB   = `{"class":"AIS","device":"stdin","type":24,"repeat":0,"mmsi":271041815,"scaled":true,"partno":1,"shiptype":60,"shiptype_text":"Passenger, all ships of this type","vendorid":"1D00014","model":0,"serial":0,"callsign":"TC6163","to_bow":0,"to_stern":15,"to_port":0,"to_starboard":5}`
+)
+
+func decodeAIS(t *testing.T, line string) Report {
+	t.Helper()
+	report, err := unmarshalAIS(defaultDecoder, []byte(line))
+	if err != nil {
+		t.Fatalf("unmarshalAIS(%s): %v", line, err)
+	}
+	return report
+}
+
+func TestUnmarshalAISTypes(t *testing.T) {
+	tests := []struct {
+		line string
+		want Report
+	}{
+		{aisType1Scaled, &AISPosition{}},
+		{`{"class":"AIS","type":3,"mmsi":1}`, &AISPosition{}},
+		{`{"class":"AIS","type":4,"mmsi":1}`, &AISBaseStation{}},
+		{`{"class":"AIS","type":11,"mmsi":1}`, &AISBaseStation{}},
+		{aisType5Unscaled, &AISStaticVoyage{}},
+		{`{"class":"AIS","type":8,"mmsi":1,"dac":1,"fid":31}`, &AISBinary{}},
+		{`{"class":"AIS","type":14,"mmsi":1,"text":"SART TEST"}`, &AISSafety{}},
+		{aisType18Missing, &AISClassBPosition{}},
+		{`{"class":"AIS","type":21,"mmsi":1,"name":"BUOY"}`, &AISAidToNavigation{}},
+		{aisType24PartA, &AISStaticData{}},
+		{`{"class":"AIS","type":27,"mmsi":1}`, &AISOther{}},
+		{`{"class":"AIS","mmsi":1}`, &AISOther{}},
+	}
+	for _, tt := range tests {
+		report := decodeAIS(t, tt.line)
+		if reflect.TypeOf(report) != reflect.TypeOf(tt.want) {
+			t.Errorf("unmarshalAIS(%s) = %T, want %T", tt.line, report, tt.want)
+		}
+		if report.ReportClass() != "AIS" {
+			t.Errorf("ReportClass() = %q, want AIS", report.ReportClass())
+		}
+	}
+
+	other := decodeAIS(t, `{"class":"AIS","type":27,"mmsi":7}`).(*AISOther)
+	if other.MMSI != 7 || string(other.Data) != `{"class":"AIS","type":27,"mmsi":7}` {
+		t.Errorf("AISOther = %+v, want MMSI 7 and the raw report", other)
+	}
+}
+
+func TestAISPositionScaling(t *testing.T) {
+	for _, line := range []string{aisType1Unscaled, aisType1Scaled} {
+		r := decodeAIS(t, line).(*AISPosition)
+		if r.MMSI != 371798000 {
+			t.Errorf("MMSI = %d", r.MMSI)
+		}
+		if lat, lon, ok := r.Position(); !ok || math.Abs(lat-48.3816) > 1e-4 || math.Abs(lon+123.3954) > 1e-4 {
+			t.Errorf("scaled %v: Position = %v, %v, %v", r.Scaled, lat, lon, ok)
+		}
+		if speed, ok := r.SpeedKnots(); !ok || math.Abs(speed-12.3) > 1e-9 {
+			t.Errorf("scaled %v: SpeedKnots = %v, %v", r.Scaled, speed, ok)
+		}
+		if course, ok := r.CourseDegrees(); !ok || math.Abs(course-224) > 1e-9 {
+			t.Errorf("scaled %v: CourseDegrees = %v, %v", r.Scaled, course, ok)
+		}
+		if turn, ok := r.RateOfTurn(); !ok || turn != -720 {
+			t.Errorf("scaled %v: RateOfTurn = %v, %v, want -720", r.Scaled, turn, ok)
+		}
+	}
+}
+
+func TestAISNotAvailable(t *testing.T) {
+	r := decodeAIS(t, aisType18Missing).(*AISClassBPosition)
+	if _, _, ok := r.Position(); ok {
+		t.Error("Position of lon 181, lat 91 is available")
+	}
+	if _, ok := r.SpeedKnots(); ok {
+		t.Error("speed 1023 is available")
+	}
+	if _, ok := r.CourseDegrees(); ok {
+		t.Error("course 3600 is available")
+	}
+	if r.Heading != 511 {
+		t.Errorf("Heading = %d, want 511", r.Heading)
+	}
+
+	tests := []struct {
+		name        string
+		value       float64
+		scaled      bool
+		unavailable float64
+		want        float64
+		ok          bool
+	}{
+		{"unscaled speed", 1022, false, 1023, 102.2, true},
+		{"unscaled speed sentinel", 1023, false, 1023, 0, false},
+		{"scaled speed", 102.2, true, 1023, 102.2, true},
+		{"scaled speed sentinel", 102.3, true, 1023, 0, false},
+		{"unscaled course", 3599, false, 3600, 359.9, true},
+		{"unscaled course sentinel", 3600, false, 3600, 0, false},
+		{"scaled course sentinel", 360, true, 3600, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := aisTenths(tt.value, tt.scaled, tt.unavailable)
+		if ok != tt.ok || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: aisTenths = %v, %v, want %v, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	positions := []struct {
+		lat, lon float64
+		scaled   bool
+		ok       bool
+	}{
+		{54600000, 108600000, false, false}, // 91, 181: not available
+		{29028978, 108600000, false, false},
+		{29028978, -74037228, false, true},
+		{91, 181, true, false},
+		{48.3816, 181, true, false},
+		{-90, -180, true, true},
+	}
+	for _, tt := range positions {
+		if _, _, ok := aisPosition(tt.lat, tt.lon, tt.scaled); ok != tt.ok {
+			t.Errorf("aisPosition(%v, %v, scaled %v) ok = %v, want %v", tt.lat, tt.lon, tt.scaled, ok, tt.ok)
+		}
+	}
+}
+
+func TestAISRateOfTurn(t *testing.T) {
+	tests := []struct {
+		turn   string
+		scaled bool
+		want   float64
+		ok     bool
+	}{
+		{`-128`, false, 0, false},
+		{`127`, false, 720, true},
+		{`-127`, false, -720, true},
+		{`0`, false, 0, true},
+		{`10`, false, (10 / 4.733) * (10 / 4.733), true},
+		{`-10`, false, -(10 / 4.733) * (10 / 4.733), true},
+		{`"nan"`, true, 0, false},
+		{`"fastright"`, true, 720, true},
+		{`"fastleft"`, true, -720, true},
+		{`4.5`, true, 4.5, true},
+		{`"-2.3"`, true, -2.3, true},
+		{`null`, true, 0, false},
+		{``, false, 0, false},
+	}
+	for _, tt := range tests {
+		r := AISPosition{AIS: AIS{Scaled: tt.scaled}, Turn: []byte(tt.turn)}
+		got, ok := r.RateOfTurn()
+		if ok != tt.ok || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("turn %s scaled %v: RateOfTurn = %v, %v, want %v, %v", tt.turn, tt.scaled, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestAISStaticVoyageDraught(t *testing.T) {
+	for _, line := range []string{aisType5Unscaled, aisType5Scaled} {
+		r := decodeAIS(t, line).(*AISStaticVoyage)
+		if d := r.DraughtMeters(); math.Abs(d-12.2) > 1e-9 {
+			t.Errorf("scaled %v: DraughtMeters = %v, want 12.2", r.Scaled, d)
+		}
+		if r.Shipname != "EVER DIADEM" || r.Destination != "NEW YORK" || r.ToBow != 225 {
+			t.Errorf("scaled %v: decoded %+v", r.Scaled, r)
+		}
+	}
+}
+
+func TestAISStaticAssembler(t *testing.T) {
+	partA := func() *AISStaticData { return decodeAIS(t, aisType24PartA).(*AISStaticData) }
+	partB := func() *AISStaticData { return decodeAIS(t, aisType24PartB).(*AISStaticData) }
+	check := func(name string, merged *AISStaticData, ok bool) {
+		t.Helper()
+		if !ok || merged == nil {
+			t.Fatalf("%s: no merged report", name)
+		}
+		if merged.MMSI != 271041815 || merged.Shipname != "PROGUY" || merged.Callsign != "TC6163" ||
+			merged.Shiptype != 60 || merged.ToStern != 15 || !merged.Complete() {
+			t.Errorf("%s: merged %+v", name, merged)
+		}
+	}
+
+	var a AISStaticAssembler
+	if _, ok := a.Add(partA()); ok {
+		t.Fatal("part A alone was complete")
+	}
+	merged, ok := a.Add(partB())
+	check("A then B", merged, ok)
+
+	if _, ok := a.Add(partB()); ok {
+		t.Fatal("part B alone was complete")
+	}
+	merged, ok = a.Add(partA())
+	check("B then A", merged, ok)
+
+	// A repeated part replaces the pending one, other vessels do not mix
+	a.Add(partA())
+	a.Add(partA())
+	other := partB()
+	other.MMSI = 1
+	if _, ok := a.Add(other); ok {
+		t.Fatal("parts of different vessels were merged")
+	}
+	merged, ok = a.Add(partB())
+	check("after a repeated part A", merged, ok)
+
+	// Reports GPSD aggregated itself pass straight through
+	whole := partB()
+	whole.Shipname = "PROGUY"
+	if merged, ok := a.Add(whole); !ok || merged != whole {
+		t.Errorf("aggregated report: %+v, %v", merged, ok)
+	}
+}
@@ -0,0 +1,150 @@
+package gopsd
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Most recent report of each class, per device and overall
+type stateCache struct {
+	mu       sync.RWMutex
+	byDevice map[cacheKey]cachedReport // Latest report per class and device
+	byClass  map[string]cachedReport   // Latest report per class from any device
+}
+
+type cacheKey struct {
+	class  string // Report class
+	device string // Originating device, empty for device-less classes
+}
+
+type cachedReport struct {
+	report   Report    // Private copy of the report, nil when kept as line
+	line     []byte    // Private copy of an AIS line, re-decoded on every load
+	received time.Time // When the report was read
+}
+
+// Keep the latest report of every class for the Last accessors.
+// Every class is decoded while the cache is enabled.
+func WithStateCache() Option {
+	return func(s *Session) {
+		s.cache = &stateCache{byDevice: map[cacheKey]cachedReport{}, byClass: map[string]cachedReport{}}
+	}
+}
+
+// Latest report of a class from device, or from any device when device is
+// empty, with the time it was received. Requires WithStateCache.
+func (s *Session) Last(class, device string) (Report, time.Time, bool) {
+	if s.cache == nil {
+		return nil, time.Time{}, false
+	}
+	return s.cache.load(s.decoder, class, device)
+}
+
+// Latest TPV from device, or from any device when device is empty
+func (s *Session) LastFix(device string) (*TPV, time.Time, bool) {
+	return lastOf[TPV](s, device)
+}
+
+// Latest SKY from device, or from any device when device is empty
+func (s *Session) LastSky(device string) (*SKY, time.Time, bool) {
+	return lastOf[SKY](s, device)
+}
+
+// Latest GST from device, or from any device when device is empty
+func (s *Session) LastGST(device string) (*GST, time.Time, bool) {
+	return lastOf[GST](s, device)
+}
+
+// Latest ATT from device, or from any device when device is empty
+func (s *Session) LastAttitude(device string) (*ATT, time.Time, bool) {
+	return lastOf[ATT](s, device)
+}
+
+// Wait until a TPV with at least minMode arrives, returning a cached one
+// straight away when the state cache already holds it
+func (s *Session) WaitForFix(ctx context.Context, minMode Mode) (*TPV, error) {
+	fixes := make(chan *TPV, 1)
+	defer On(s, func(tpv *TPV) {
+		if tpv.Mode < minMode {
+			return
+		}
+		fix := *tpv
+		select {
+		case fixes <- &fix:
+		default:
+		}
+	}).Unsubscribe()
+
+	if tpv, _, ok := s.LastFix(""); ok && tpv.Mode >= minMode {
+		return tpv, nil
+	}
+
+	select {
+	case fix := <-fixes:
+		return fix, nil
+	case <-s.done:
+		return nil, s.Err()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Typed copy of the latest report of T
+func lastOf[T Report](s *Session, device string) (*T, time.Time, bool) {
+	var zero T
+	report, received, ok := s.Last(zero.ReportClass(), device)
+	if !ok {
+		return nil, time.Time{}, false
+	}
+	return any(report).(*T), received, true
+}
+
+// Record a private copy of a freshly decoded report. AIS reports have no
+// clone, their line is kept instead and decoded afresh for each caller.
+func (c *stateCache) store(class string, line []byte, report Report) {
+	entry := cachedReport{received: time.Now()}
+	if rt, ok := reportTypes[class]; ok {
+		entry.report = rt.clone(report)
+	} else {
+		entry.line = append([]byte(nil), line...)
+	}
+
+	var device string
+	eachMember(line, func(key, value []byte) bool {
+		if string(key) != "device" {
+			return true
+		}
+		if len(value) >= 2 && value[0] == '"' {
+			device = string(value[1 : len(value)-1])
+		}
+		return false
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.byDevice[cacheKey{class: class, device: device}] = entry
+	c.byClass[class] = entry
+}
+
+// Fetch a copy of a cached report so callers cannot alias the cache
+func (c *stateCache) load(d Decoder, class, device string) (Report, time.Time, bool) {
+	c.mu.RLock()
+	entry, ok := c.byClass[class]
+	if device != "" {
+		entry, ok = c.byDevice[cacheKey{class: class, device: device}]
+	}
+	c.mu.RUnlock()
+
+	if !ok {
+		return nil, time.Time{}, false
+	}
+	if entry.report != nil {
+		return reportTypes[class].clone(entry.report), entry.received, true
+	}
+	report, err := unmarshalAIS(d, entry.line)
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	return report, entry.received, true
+}
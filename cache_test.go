@@ -0,0 +1,51 @@
+package gopsd
+
+import "testing"
+
+func TestLastReturnsPrivateCopies(t *testing.T) {
+	lines := []string{
+		`{"class":"TPV","device":"/dev/ttyACM0","mode":3,"lat":1.5,"lon":2.5}`,
+		`{"class":"SKY","device":"/dev/ttyACM0","satellites":[{"PRN":5,"el":31,"az":86,"ss":45,"used":true},{"PRN":13,"el":44,"az":53,"ss":47,"used":true}]}`,
+		`{"class":"AIS","device":"stdin","type":1,"repeat":0,"mmsi":244670316,"scaled":true,"status":0,"speed":0.1,"lon":4.9,"lat":52.3,"course":12.5,"heading":511}`,
+	}
+	s := &Session{decoder: defaultDecoder, maxLine: defaultMaxLineSize, filters: map[string][]*filterEntry{}}
+	WithStateCache()(s)
+
+	dispatched := map[string]Report{}
+	s.AddFilter(AllClasses, func(r interface{}) { dispatched[r.(Report).ReportClass()] = r.(Report) })
+	On(s, func(sky *SKY) { sky.Satellites[0].PRN = 99 }) // filters must not reach the cache
+	for _, line := range lines {
+		s.handleLine([]byte(line))
+	}
+
+	for _, class := range []string{"TPV", "SKY", "AIS"} {
+		first, _, ok := s.Last(class, "")
+		if !ok {
+			t.Fatalf("Last(%q) found nothing", class)
+		}
+		if first == dispatched[class] {
+			t.Errorf("Last(%q) returned the report handed to filters", class)
+		}
+		second, _, _ := s.Last(class, "")
+		if first == second {
+			t.Errorf("Last(%q) returned the same report twice", class)
+		}
+	}
+
+	ais, _, _ := s.Last("AIS", "stdin")
+	ais.(*AISPosition).MMSI = 1
+	again, _, _ := s.Last("AIS", "stdin")
+	if mmsi := again.(*AISPosition).MMSI; mmsi != 244670316 {
+		t.Errorf("mutating a returned AIS report changed the cache, MMSI %d", mmsi)
+	}
+
+	sky, _, _ := s.LastSky("/dev/ttyACM0")
+	if prn := sky.Satellites[0].PRN; prn != 5 {
+		t.Errorf("a filter changed the cached SKY, PRN %d", prn)
+	}
+	sky.Satellites[1].PRN = 99
+	sky, _, _ = s.LastSky("/dev/ttyACM0")
+	if prn := sky.Satellites[1].PRN; prn != 13 {
+		t.Errorf("mutating a returned SKY changed the cache, PRN %d", prn)
+	}
+}
@@ -0,0 +1,21 @@
+package gopsd
+
+import "encoding/json"
+
+// Decoder turns a JSON report line into a report struct
+type Decoder interface {
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// Decoder built on encoding/json, pure Go and portable to every target
+type StdDecoder struct{}
+
+func (StdDecoder) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// Decode reports with d instead of the platform default, which is sonic
+// on amd64 and arm64 and StdDecoder elsewhere or with the gopsd_std tag
+func WithDecoder(d Decoder) Option {
+	return func(s *Session) { s.decoder = d }
+}
@@ -0,0 +1,14 @@
+//go:build (amd64 || arm64) && !gopsd_std
+
+package gopsd
+
+import "github.com/bytedance/sonic"
+
+// Decoder built on sonic's JIT, the default on amd64 and arm64
+type SonicDecoder struct{}
+
+func (SonicDecoder) Unmarshal(data []byte, v interface{}) error {
+	return sonic.Unmarshal(data, v)
+}
+
+var defaultDecoder Decoder = SonicDecoder{}
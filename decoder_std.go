@@ -0,0 +1,5 @@
+//go:build !(amd64 || arm64) || gopsd_std
+
+package gopsd
+
+var defaultDecoder Decoder = StdDecoder{}
@@ -0,0 +1,203 @@
+package gopsd
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Device event constants
+const (
+	DeviceAdded   DeviceEventKind = iota // Device activated
+	DeviceRemoved                        // Device deactivated or unplugged
+	DeviceChanged                        // Settings of an active device changed
+)
+
+type DeviceEventKind byte // Kind of device hotplug event
+
+// Hotplug event derived from DEVICE and DEVICES reports
+type DeviceEvent struct {
+	Kind   DeviceEventKind // What happened to the device
+	Device DEVICE          // Device state reported by GPSD
+}
+
+func (k DeviceEventKind) String() string {
+	switch k {
+	case DeviceAdded:
+		return "added"
+	case DeviceRemoved:
+		return "removed"
+	case DeviceChanged:
+		return "changed"
+	}
+	return "unknown"
+}
+
+// Settings sent with ?DEVICE, nil fields are left unchanged
+type deviceConfig struct {
+	Class    string   `json:"class"`
+	Path     string   `json:"path,omitempty"`
+	Bps      *int     `json:"bps,omitempty"`
+	Parity   *string  `json:"parity,omitempty"`
+	Stopbits *int     `json:"stopbits,omitempty"`
+	Cycle    *float64 `json:"cycle,omitempty"`
+	Native   *int     `json:"native,omitempty"`
+	Hexdata  *string  `json:"hexdata,omitempty"`
+}
+
+// List the devices GPSD currently knows about
+func (s *Session) Devices(ctx context.Context) ([]DEVICE, error) {
+	reply, err := s.request(ctx, []byte("?DEVICES;"), "DEVICES", nil)
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*DEVICES).Devices, nil
+}
+
+// Call fn when devices are added, removed or changed. GPSD announces
+// activation with a DEVICE report carrying the "activated" time and
+// removal with "activated":0. The first DEVICES list seeds the known
+// devices, later lists (e.g. after a reconnect) are diffed against them.
+func (s *Session) OnDeviceEvent(fn func(DeviceEvent)) *Handle {
+	var known map[string]DEVICE
+	return s.addFilter(func(r interface{}) {
+		switch report := r.(type) {
+		case *DEVICE:
+			previous, seen := known[report.Path]
+			if known == nil {
+				known = map[string]DEVICE{}
+			}
+			switch {
+			case report.Activated.IsZero():
+				delete(known, report.Path)
+				fn(DeviceEvent{Kind: DeviceRemoved, Device: *report})
+			case seen && previous == *report:
+				// Reply to a query, nothing changed
+			case seen:
+				known[report.Path] = *report
+				fn(DeviceEvent{Kind: DeviceChanged, Device: *report})
+			default:
+				known[report.Path] = *report
+				fn(DeviceEvent{Kind: DeviceAdded, Device: *report})
+			}
+		case *DEVICES:
+			current := make(map[string]DEVICE, len(report.Devices))
+			for _, device := range report.Devices {
+				current[device.Path] = device
+			}
+			if known != nil {
+				for path, device := range known {
+					if _, ok := current[path]; !ok {
+						fn(DeviceEvent{Kind: DeviceRemoved, Device: device})
+					}
+				}
+				for path, device := range current {
+					if _, ok := known[path]; !ok {
+						fn(DeviceEvent{Kind: DeviceAdded, Device: device})
+					}
+				}
+			}
+			known = current
+		}
+	}, "DEVICE", "DEVICES")
+}
+
+// Query the state of a device, an empty path selects GPSD's default device
+func (s *Session) Device(ctx context.Context, path string) (*DEVICE, error) {
+	return s.configureDevice(ctx, deviceConfig{Path: path})
+}
+
+// Change the serial speed and framing of a device.
+// Parity is "N", "O" or "E" and stopbits is 1 or 2.
+func (s *Session) SetDeviceSpeed(ctx context.Context, path string, bps int, parity string, stopbits int) (*DEVICE, error) {
+	if bps <= 0 {
+		return nil, fmt.Errorf("%w: bps %d", ErrInvalidSetting, bps)
+	}
+	if parity != "N" && parity != "O" && parity != "E" {
+		return nil, fmt.Errorf("%w: parity %q", ErrInvalidSetting, parity)
+	}
+	if stopbits != 1 && stopbits != 2 {
+		return nil, fmt.Errorf("%w: stopbits %d", ErrInvalidSetting, stopbits)
+	}
+
+	if _, err := s.writableDevice(ctx, path); err != nil {
+		return nil, err
+	}
+	return s.configureDevice(ctx, deviceConfig{Path: path, Bps: &bps, Parity: &parity, Stopbits: &stopbits})
+}
+
+// Change the update cycle of a device, e.g. 200ms for 5 Hz.
+// The cycle may not be shorter than the device's Mincycle.
+func (s *Session) SetDeviceCycle(ctx context.Context, path string, cycle time.Duration) (*DEVICE, error) {
+	device, err := s.writableDevice(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	seconds := cycle.Seconds()
+	if seconds <= 0 || (device.Mincycle > 0 && seconds < device.Mincycle) {
+		return nil, fmt.Errorf("%w: cycle %v below minimum %vs", ErrInvalidSetting, cycle, device.Mincycle)
+	}
+	return s.configureDevice(ctx, deviceConfig{Path: path, Cycle: &seconds})
+}
+
+// Switch a device between NMEA (false) and its native binary protocol (true)
+func (s *Session) SetDeviceNative(ctx context.Context, path string, native bool) (*DEVICE, error) {
+	if _, err := s.writableDevice(ctx, path); err != nil {
+		return nil, err
+	}
+
+	mode := 0
+	if native {
+		mode = 1
+	}
+	return s.configureDevice(ctx, deviceConfig{Path: path, Native: &mode})
+}
+
+// Deliver raw bytes, e.g. a UBXFrame or SiRFFrame, to the receiver at path
+func (s *Session) SendHexdata(ctx context.Context, path string, data []byte) (*DEVICE, error) {
+	if path == "" || len(data) == 0 {
+		return nil, fmt.Errorf("%w: hexdata needs a device path and data", ErrInvalidSetting)
+	}
+	if _, err := s.writableDevice(ctx, path); err != nil {
+		return nil, err
+	}
+
+	hexdata := hex.EncodeToString(data)
+	return s.configureDevice(ctx, deviceConfig{Path: path, Hexdata: &hexdata})
+}
+
+// Fetch a device and refuse to reconfigure it when read-only
+func (s *Session) writableDevice(ctx context.Context, path string) (*DEVICE, error) {
+	device, err := s.Device(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if device.Readonly {
+		return nil, fmt.Errorf("%w: %s", ErrReadonly, device.Path)
+	}
+	return device, nil
+}
+
+// Send ?DEVICE and wait for the resulting state of that device
+func (s *Session) configureDevice(ctx context.Context, config deviceConfig) (*DEVICE, error) {
+	command := []byte("?DEVICE;")
+	if config != (deviceConfig{}) {
+		config.Class = "DEVICE"
+		body, err := json.Marshal(&config)
+		if err != nil {
+			return nil, err
+		}
+		command = append(append([]byte("?DEVICE="), body...), ';')
+	}
+
+	reply, err := s.request(ctx, command, "DEVICE", func(r Report) bool {
+		return config.Path == "" || r.(*DEVICE).Path == config.Path
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*DEVICE), nil
+}
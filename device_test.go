@@ -0,0 +1,39 @@
+package gopsd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOnDeviceEventAddAndRemove(t *testing.T) {
+	f := newFakeGPSD(t, false,
+		`{"class":"DEVICE","path":"/dev/ttyUSB0","driver":"u-blox","activated":"2024-01-01T00:00:00.000Z","flags":1,"native":1,"bps":9600,"parity":"N","stopbits":1,"cycle":1.00}`,
+		`{"class":"DEVICE","path":"/dev/ttyUSB0","activated":0}`,
+	)
+	errs := make(chan error, 4)
+	s, err := Dial(f.addr(), WithErrorHook(func(err error) { errs <- err }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	events := make(chan DeviceEvent, 4)
+	s.OnDeviceEvent(func(e DeviceEvent) { events <- e })
+	s.Watch()
+
+	for _, want := range []DeviceEventKind{DeviceAdded, DeviceRemoved} {
+		select {
+		case e := <-events:
+			if e.Kind != want || e.Device.Path != "/dev/ttyUSB0" {
+				t.Fatalf("got %s %s, want %s /dev/ttyUSB0", e.Kind, e.Device.Path, want)
+			}
+			if want == DeviceRemoved && !e.Device.Activated.IsZero() {
+				t.Fatalf("removed device activated at %v", e.Device.Activated)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no %s event", want)
+		case err := <-errs:
+			t.Fatalf("waiting for %s event: %v", want, err)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package gopsd
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors reported by sessions, match with errors.Is
+var (
+	ErrClosed         = errors.New("GPSD socket is already closed")
+	ErrConnectionLost = errors.New("GPSD connection lost")
+	ErrLineTooLong    = errors.New("GPSD report exceeds the maximum line size")
+	ErrMalformedJSON  = errors.New("malformed GPSD report")
+	ErrUnknownClass   = errors.New("unknown GPSD report class")
+	ErrWrite          = errors.New("GPSD write failed")
+	ErrProtocol       = errors.New("GPSD protocol version too old")
+	ErrReadonly       = errors.New("GPSD device is read-only")
+	ErrInvalidSetting = errors.New("invalid GPSD device setting")
+)
+
+// Error carrying the raw line that caused it
+type ProtocolError struct {
+	Kind error  // One of the Err* sentinels
+	Line []byte // Offending report or command, may be truncated
+	Err  error  // Underlying error, if any
+}
+
+func (e *ProtocolError) Error() string {
+	msg := e.Kind.Error()
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	if len(e.Line) > 0 {
+		msg += fmt.Sprintf(" (line %q)", e.Line)
+	}
+	return msg
+}
+
+func (e *ProtocolError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// Call fn for every read, decode and write error, including ones the
+// session recovers from. fn runs on the watcher goroutine.
+func WithErrorHook(fn func(error)) Option {
+	return func(s *Session) { s.onError = fn }
+}
+
+// Reason the watcher stopped, nil while it is still running
+func (s *Session) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Notify the error hook
+func (s *Session) reportError(err error) {
+	if s.onError != nil {
+		s.onError(err)
+	}
+}
+
+// Build a ProtocolError owning a copy of line
+func newProtocolError(kind error, line []byte, err error) *ProtocolError {
+	return &ProtocolError{Kind: kind, Line: append([]byte(nil), line...), Err: err}
+}
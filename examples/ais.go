@@ -0,0 +1,33 @@
+package examples
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/AryaanSheth/gopsd"
+)
+
+// Example: Track vessels from an AIS receiver
+func ais() {
+	gps, err := gopsd.Dial(gopsd.DefaultAddress)
+	if err != nil {
+		log.Fatalf("Failed to connect to GPSD: %v", err)
+	}
+	defer gps.Close()
+
+	gopsd.On(gps, func(r *gopsd.AISPosition) {
+		if lat, lon, ok := r.Position(); ok {
+			speed, _ := r.SpeedKnots()
+			fmt.Printf("AIS - %09d at %f, %f making %.1f kn\n", r.MMSI, lat, lon, speed)
+		}
+	})
+
+	var statics gopsd.AISStaticAssembler
+	gopsd.On(gps, func(r *gopsd.AISStaticData) {
+		if vessel, ok := statics.Add(r); ok {
+			fmt.Printf("AIS - %09d is %s (%s)\n", vessel.MMSI, vessel.Shipname, vessel.Callsign)
+		}
+	})
+
+	<-gps.Watch()
+}
@@ -1,6 +1,7 @@
 package examples
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
@@ -11,7 +12,7 @@ import (
 type CustomGPSData struct {
 	Class  string  `json:"class"`
 	Device string  `json:"device"`
-	Status int     `json:"status,omitempty"`
+	Status string  `json:"status,omitempty"`
 	Ept    float64 `json:"ept,omitempty"`
 	Lat    float64 `json:"lat,omitempty"`
 	Lon    float64 `json:"lon,omitempty"`
@@ -24,30 +25,28 @@ type CustomGPSData struct {
 
 // Example: Populate a Custom Struct
 func customStruct() {
-	gps, err := gopsd.Dial(gopsd.DefaultAddress)
+	gps, err := gopsd.Dial(gopsd.DefaultAddress, gopsd.WithStateCache())
 	if err != nil {
 		log.Fatalf("Failed to connect to GPSD: %v", err)
 	}
 	defer gps.Close()
 
-	var tpv *gopsd.TPV
-	var gst *gopsd.GST
+	gps.Watch()
 
-	gps.AddFilter("TPV", func(r interface{}) { tpv = r.(*gopsd.TPV) })
-	gps.AddFilter("GST", func(r interface{}) { gst = r.(*gopsd.GST) })
-
-	done := gps.Watch()
-	go func() {
-		<-done
-	}()
-	time.Sleep(3 * time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	tpv, err := gps.WaitForFix(ctx, gopsd.Mode3D)
+	if err != nil {
+		log.Fatalf("No 3D fix: %v", err)
+	}
+	gst, _, _ := gps.LastGST(tpv.Device)
 
 	customData := CustomGPSData{}
 
 	if tpv != nil {
 		customData.Class = tpv.Class
 		customData.Device = tpv.Device
-		customData.Status = tpv.Status
+		customData.Status = tpv.Status.String()
 		customData.Ept = tpv.Ept
 		customData.Lat = tpv.Lat
 		customData.Lon = tpv.Lon
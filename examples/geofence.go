@@ -0,0 +1,44 @@
+package examples
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/AryaanSheth/gopsd"
+	"github.com/AryaanSheth/gopsd/geo"
+	"github.com/AryaanSheth/gopsd/geofence"
+)
+
+// Example: Report fence enter, exit and dwell events
+func geofencing() {
+	gps, err := gopsd.Dial(gopsd.DefaultAddress)
+	if err != nil {
+		log.Fatalf("Failed to connect to GPSD: %v", err)
+	}
+	defer gps.Close()
+
+	monitor := geofence.Watch(gps, func(e geofence.Event) {
+		fmt.Printf("%s %s %s at %v\n", e.Device, e.Kind, e.Fence.ID, e.Time)
+	}, geofence.WithMinMargin(3))
+	defer monitor.Stop()
+
+	monitor.Add(&geofence.Fence{
+		ID:    "depot",
+		Shape: geofence.Circle{Center: geo.Point{Lat: 51.5007, Lon: -0.1246}, Radius: 150},
+		Dwell: 5 * time.Minute,
+	})
+	if data, err := os.ReadFile("fences.geojson"); err == nil {
+		fences, err := geofence.ParseGeoJSON(data)
+		if err != nil {
+			log.Fatalf("Invalid fences: %v", err)
+		}
+		monitor.Add(fences...)
+	}
+
+	select {
+	case <-gps.Watch():
+	case <-time.After(time.Hour):
+	}
+}
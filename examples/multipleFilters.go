@@ -17,20 +17,15 @@ func multipleFilters() {
 	}
 	defer gps.Close()
 
-	gps.AddFilter("TPV", func(data interface{}) {
-		if tpv, ok := data.(*gopsd.TPV); ok {
-			fmt.Printf("TPV - Mode: %d, Time: %v\n", tpv.Mode, tpv.Time)
-		} else {
-			fmt.Printf("TPV - %v\n", data)
+	gopsd.On(gps, func(tpv *gopsd.TPV) {
+		fmt.Printf("TPV - Mode: %s, Time: %v\n", tpv.Mode, tpv.Time)
+		if tpv.Has("lat") && tpv.Has("lon") {
+			fmt.Printf("TPV - Lat: %f, Lon: %f\n", tpv.Lat, tpv.Lon)
 		}
 	})
 
-	gps.AddFilter("SKY", func(data interface{}) {
-		if sky, ok := data.(*gopsd.SKY); ok {
-			fmt.Printf("SKY - %d satellites\n", len(sky.Satellites))
-		} else {
-			fmt.Printf("SKY - %v\n", data)
-		}
+	gopsd.On(gps, func(sky *gopsd.SKY) {
+		fmt.Printf("SKY - %d satellites\n", len(sky.Satellites))
 	})
 
 	done := gps.Watch()
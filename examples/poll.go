@@ -0,0 +1,33 @@
+package examples
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/AryaanSheth/gopsd"
+)
+
+// Example: Poll the current fix once a minute without callbacks
+func poll() {
+	gps, err := gopsd.Dial(gopsd.DefaultAddress)
+	if err != nil {
+		log.Fatalf("Failed to connect to GPSD: %v", err)
+	}
+	defer gps.Close()
+
+	for range time.Tick(time.Minute) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		report, err := gps.Poll(ctx)
+		cancel()
+		if err != nil {
+			log.Printf("Poll failed: %v", err)
+			continue
+		}
+
+		for _, tpv := range report.TPV {
+			fmt.Printf("%s - Lat: %f, Lon: %f\n", tpv.Device, tpv.Lat, tpv.Lon)
+		}
+	}
+}
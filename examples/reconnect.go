@@ -0,0 +1,36 @@
+package examples
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/AryaanSheth/gopsd"
+)
+
+// Example: Survive gpsd restarts with automatic reconnection
+func reconnect() {
+	backoff := gopsd.DefaultBackoff
+	backoff.MaxRetries = 20
+
+	gps, err := gopsd.Dial(gopsd.DefaultAddress,
+		gopsd.WithReconnect(backoff),
+		gopsd.WithStateHook(func(state gopsd.ConnState) {
+			log.Printf("GPSD connection %s", state)
+		}),
+	)
+	if err != nil {
+		log.Fatalf("Failed to connect to GPSD: %v", err)
+	}
+	defer gps.Close()
+
+	gopsd.On(gps, func(tpv *gopsd.TPV) {
+		fmt.Printf("TPV - Lat: %f, Lon: %f\n", tpv.Lat, tpv.Lon)
+	})
+
+	select {
+	case <-gps.Watch():
+		fmt.Println("Gave up reconnecting to GPSD")
+	case <-time.After(10 * time.Minute):
+	}
+}
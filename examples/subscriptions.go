@@ -0,0 +1,38 @@
+package examples
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/AryaanSheth/gopsd"
+)
+
+// Example: Decouple a fast consumer from a slow one with channels
+func subscriptions() {
+	gps, err := gopsd.Dial(gopsd.DefaultAddress)
+	if err != nil {
+		log.Fatalf("Failed to connect to GPSD: %v", err)
+	}
+	defer gps.Close()
+
+	fixes := gopsd.Subscribe[gopsd.TPV](gps, 1, gopsd.DropOldest)
+	skies := gopsd.Subscribe[gopsd.SKY](gps, 64, gopsd.DropNewest)
+	defer fixes.Unsubscribe()
+	defer skies.Unsubscribe()
+
+	go func() {
+		for sky := range skies.C {
+			fmt.Printf("SKY - %d satellites (%d dropped)\n", len(sky.Satellites), skies.Dropped())
+		}
+	}()
+
+	done := gps.Watch()
+	for {
+		select {
+		case tpv := <-fixes.C:
+			fmt.Printf("TPV - Lat: %f, Lon: %f\n", tpv.Lat, tpv.Lon)
+		case <-done:
+			return
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package examples
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/AryaanSheth/gopsd"
+)
+
+// Example: Tie the watcher to a cancellable context
+func watchContext() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	gps, err := gopsd.DialContext(ctx, gopsd.DefaultAddress)
+	if err != nil {
+		log.Fatalf("Failed to connect to GPSD: %v", err)
+	}
+
+	gopsd.On(gps, func(tpv *gopsd.TPV) {
+		fmt.Printf("TPV - Time: %v\n", tpv.Time)
+	})
+
+	if err := gps.WatchContext(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		log.Fatalf("Watch stopped: %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package examples
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/AryaanSheth/gopsd"
+)
+
+// Example: Watch PPS reports from a single receiver
+func watchWith() {
+	gps, err := gopsd.Dial(gopsd.DefaultAddress)
+	if err != nil {
+		log.Fatalf("Failed to connect to GPSD: %v", err)
+	}
+	defer gps.Close()
+
+	gopsd.On(gps, func(pps *gopsd.PPS) {
+		fmt.Printf("PPS - %s offset: %.0f ns\n", pps.Device, pps.ClockNSec-pps.RealNSec)
+	})
+
+	enable, device := true, "/dev/ttyACM0"
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	applied, err := gps.WatchWith(ctx, gopsd.WATCH{Enable: &enable, JSON: &enable, PPS: &enable, Device: &device})
+	if err != nil {
+		log.Fatalf("Failed to start watching: %v", err)
+	}
+	fmt.Printf("Watching %s with pps=%v\n", *applied.Device, *applied.PPS)
+
+	<-gps.Done()
+}
@@ -0,0 +1,120 @@
+package geo
+
+import "math"
+
+const (
+	vincentyTolerance = 1e-12 // Convergence threshold on lambda (radians)
+	vincentyMaxIter   = 200
+)
+
+// Great circle distance between two points on a sphere of EarthRadius.
+// Accurate to about 0.5% which is plenty for short ranges and display.
+func Haversine(a, b Point) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLat, dLon := lat2-lat1, radians(b.Lon-a.Lon)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadius * math.Asin(math.Sqrt(min(h, 1)))
+}
+
+// Distance between two points on the WGS84 ellipsoid using Vincenty's
+// inverse formula, accurate to within a millimeter. Nearly antipodal
+// points may not converge, in which case ErrNoConvergence is returned.
+func Vincenty(a, b Point) (float64, error) {
+	L := radians(b.Lon - a.Lon)
+	U1 := math.Atan((1 - Flattening) * math.Tan(radians(a.Lat)))
+	U2 := math.Atan((1 - Flattening) * math.Tan(radians(b.Lat)))
+	sinU1, cosU1 := math.Sincos(U1)
+	sinU2, cosU2 := math.Sincos(U2)
+
+	lambda := L
+	var sinSigma, cosSigma, sigma, cosSqAlpha, cos2SigmaM float64
+	for i := 0; ; i++ {
+		if i == vincentyMaxIter {
+			return 0, ErrNoConvergence
+		}
+		sinLambda, cosLambda := math.Sincos(lambda)
+		sinSigma = math.Hypot(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+		if sinSigma == 0 {
+			return 0, nil // coincident points
+		}
+		cosSigma = sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma = math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cosSqAlpha = 1 - sinAlpha*sinAlpha
+		cos2SigmaM = 0
+		if cosSqAlpha != 0 { // both points on the equator otherwise
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cosSqAlpha
+		}
+		C := Flattening / 16 * cosSqAlpha * (4 + Flattening*(4-3*cosSqAlpha))
+		prev := lambda
+		lambda = L + (1-C)*Flattening*sinAlpha*
+			(sigma+C*sinSigma*(cos2SigmaM+C*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(lambda-prev) < vincentyTolerance {
+			break
+		}
+		if math.Abs(lambda) > math.Pi {
+			return 0, ErrNoConvergence
+		}
+	}
+
+	uSq := cosSqAlpha * (SemiMajorAxis*SemiMajorAxis - SemiMinorAxis*SemiMinorAxis) / (SemiMinorAxis * SemiMinorAxis)
+	A := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+	B := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+	deltaSigma := B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+		B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+
+	return SemiMinorAxis * A * (sigma - deltaSigma), nil
+}
+
+// Distance on the WGS84 ellipsoid, falling back to Haversine where
+// Vincenty does not converge
+func Distance(a, b Point) float64 {
+	if d, err := Vincenty(a, b); err == nil {
+		return d
+	}
+	return Haversine(a, b)
+}
+
+// Initial great circle bearing from a towards b, degrees from true north
+func InitialBearing(a, b Point) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLon := radians(b.Lon - a.Lon)
+
+	y := math.Sin(dLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLon)
+	return normalizeBearing(degrees(math.Atan2(y, x)))
+}
+
+// Great circle bearing on arrival at b when coming from a, degrees from true north
+func FinalBearing(a, b Point) float64 {
+	return normalizeBearing(InitialBearing(b, a) + 180)
+}
+
+// Point reached travelling distance meters from p along the great circle
+// starting at the given bearing (degrees from true north)
+func Destination(p Point, distance, bearing float64) Point {
+	lat1, lon1 := radians(p.Lat), radians(p.Lon)
+	theta := radians(bearing)
+	delta := distance / EarthRadius
+
+	sinLat2 := math.Sin(lat1)*math.Cos(delta) + math.Cos(lat1)*math.Sin(delta)*math.Cos(theta)
+	lat2 := math.Asin(max(-1, min(sinLat2, 1)))
+	lon2 := lon1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(lat1),
+		math.Cos(delta)-math.Sin(lat1)*sinLat2)
+	return Point{Lat: degrees(lat2), Lon: normalizeLon(degrees(lon2))}
+}
+
+// Point halfway along the great circle between a and b
+func Midpoint(a, b Point) Point {
+	lat1, lon1 := radians(a.Lat), radians(a.Lon)
+	lat2 := radians(b.Lat)
+	dLon := radians(b.Lon - a.Lon)
+
+	bx := math.Cos(lat2) * math.Cos(dLon)
+	by := math.Cos(lat2) * math.Sin(dLon)
+	lat := math.Atan2(math.Sin(lat1)+math.Sin(lat2), math.Hypot(math.Cos(lat1)+bx, by))
+	lon := lon1 + math.Atan2(by, math.Cos(lat1)+bx)
+	return Point{Lat: degrees(lat), Lon: normalizeLon(degrees(lon))}
+}
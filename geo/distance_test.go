@@ -0,0 +1,76 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// Land's End and John o' Groats, the worked example of the spherical formulas
+var (
+	landsEnd     = Point{Lat: 50.0663889, Lon: -5.7147222}
+	johnOGroats  = Point{Lat: 58.6438889, Lon: -3.07}
+	flindersPeak = Point{Lat: -37.95103342, Lon: 144.42486789}
+	buninyong    = Point{Lat: -37.65282114, Lon: 143.92649554}
+)
+
+func near(got, want, tolerance float64) bool {
+	return math.Abs(got-want) <= tolerance
+}
+
+func TestVincenty(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Point
+		want float64
+	}{
+		{"Flinders Peak to Buninyong", flindersPeak, buninyong, 54972.271},
+		{"quarter of the equator", Point{0, 0}, Point{0, 90}, 10018754.171},
+		{"quarter of a meridian", Point{0, 0}, Point{90, 0}, 10001965.729},
+		{"coincident", buninyong, buninyong, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Vincenty(tt.a, tt.b)
+			if err != nil || !near(got, tt.want, 0.001) {
+				t.Errorf("Vincenty = %.4f, %v, want %.3f", got, err, tt.want)
+			}
+		})
+	}
+
+	antipodal := Point{Lat: 0.5, Lon: 179.7}
+	if _, err := Vincenty(Point{}, antipodal); !errors.Is(err, ErrNoConvergence) {
+		t.Errorf("nearly antipodal Vincenty error = %v, want ErrNoConvergence", err)
+	}
+	if d := Distance(Point{}, antipodal); !near(d, Haversine(Point{}, antipodal), 1e-6) {
+		t.Errorf("Distance did not fall back to Haversine, got %v", d)
+	}
+}
+
+func TestSphericalFormulas(t *testing.T) {
+	if d := Haversine(landsEnd, johnOGroats); !near(d, 968.9e3, 100) {
+		t.Errorf("Haversine = %.0f, want 968.9 km", d)
+	}
+	if b := InitialBearing(landsEnd, johnOGroats); !near(b, 9.1197, 1e-3) {
+		t.Errorf("InitialBearing = %.4f, want 9.1197", b)
+	}
+	if b := FinalBearing(landsEnd, johnOGroats); !near(b, 11.2752, 1e-3) {
+		t.Errorf("FinalBearing = %.4f, want 11.2752", b)
+	}
+	if b := InitialBearing(Point{0, 10}, Point{0, 5}); !near(b, 270, 1e-9) {
+		t.Errorf("InitialBearing due west = %v, want 270", b)
+	}
+
+	mid := Midpoint(landsEnd, johnOGroats)
+	if !near(mid.Lat, 54.3622, 1e-4) || !near(mid.Lon, -4.5306, 1e-4) {
+		t.Errorf("Midpoint = %+v, want 54.3622, -4.5306", mid)
+	}
+
+	dest := Destination(Point{Lat: 53.3205556, Lon: -1.7297222}, 124.8e3, 96.0216667)
+	if !near(dest.Lat, 53.1883, 1e-4) || !near(dest.Lon, 0.1333, 1e-4) {
+		t.Errorf("Destination = %+v, want 53.1883, 0.1333", dest)
+	}
+	if dest := Destination(Point{0, 179}, 200e3, 90); dest.Lon > -178 || dest.Lon < -180 {
+		t.Errorf("Destination across the antimeridian = %+v, want a longitude near -179.2", dest)
+	}
+}
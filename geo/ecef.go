@@ -0,0 +1,84 @@
+package geo
+
+import "math"
+
+// Earth-centered, earth-fixed cartesian coordinates (meters or meters per second)
+type ECEF struct {
+	X float64
+	Y float64
+	Z float64
+}
+
+// Local east, north, up coordinates relative to a reference point
+type ENU struct {
+	E float64 // East (meters or meters per second)
+	N float64 // North (meters or meters per second)
+	U float64 // Up (meters or meters per second)
+}
+
+// Square of the first eccentricity of the WGS84 ellipsoid
+const eccSq = Flattening * (2 - Flattening)
+
+// Convert a geodetic position and height above the ellipsoid (meters) to ECEF
+func ToECEF(p Point, altHAE float64) ECEF {
+	sinLat, cosLat := math.Sincos(radians(p.Lat))
+	sinLon, cosLon := math.Sincos(radians(p.Lon))
+	n := SemiMajorAxis / math.Sqrt(1-eccSq*sinLat*sinLat) // prime vertical radius
+
+	return ECEF{
+		X: (n + altHAE) * cosLat * cosLon,
+		Y: (n + altHAE) * cosLat * sinLon,
+		Z: (n*(1-eccSq) + altHAE) * sinLat,
+	}
+}
+
+// Convert ECEF coordinates to a geodetic position and height above the
+// ellipsoid (meters) using Heikkinen's closed form solution
+func FromECEF(e ECEF) (Point, float64) {
+	const (
+		a2 = SemiMajorAxis * SemiMajorAxis
+		b2 = SemiMinorAxis * SemiMinorAxis
+	)
+	p := math.Hypot(e.X, e.Y)
+	lon := degrees(math.Atan2(e.Y, e.X))
+	if p < 1e-9 { // on the polar axis
+		return Point{Lat: math.Copysign(90, e.Z), Lon: lon}, math.Abs(e.Z) - SemiMinorAxis
+	}
+
+	z2 := e.Z * e.Z
+	F := 54 * b2 * z2
+	G := p*p + (1-eccSq)*z2 - eccSq*(a2-b2)
+	c := eccSq * eccSq * F * p * p / (G * G * G)
+	s := math.Cbrt(1 + c + math.Sqrt(c*c+2*c))
+	k := s + 1 + 1/s
+	P := F / (3 * k * k * G * G)
+	Q := math.Sqrt(1 + 2*eccSq*eccSq*P)
+	r0 := -P*eccSq*p/(1+Q) +
+		math.Sqrt(max(a2/2*(1+1/Q)-P*(1-eccSq)*z2/(Q*(1+Q))-P*p*p/2, 0))
+	d := p - eccSq*r0
+	U := math.Sqrt(d*d + z2)
+	V := math.Sqrt(d*d + (1-eccSq)*z2)
+	z0 := b2 * e.Z / (SemiMajorAxis * V)
+
+	lat := degrees(math.Atan((e.Z + (a2-b2)/b2*z0) / p))
+	return Point{Lat: lat, Lon: lon}, U * (1 - b2/(SemiMajorAxis*V))
+}
+
+// Position of e in the local tangent plane at ref, at refAlt meters above the ellipsoid
+func ToENU(e ECEF, ref Point, refAlt float64) ENU {
+	origin := ToECEF(ref, refAlt)
+	return RotateENU(ECEF{X: e.X - origin.X, Y: e.Y - origin.Y, Z: e.Z - origin.Z}, ref)
+}
+
+// Rotate an ECEF vector, e.g. the Ecefvx/vy/vz velocity of a TPV, into
+// the local east, north, up axes at ref
+func RotateENU(v ECEF, ref Point) ENU {
+	sinLat, cosLat := math.Sincos(radians(ref.Lat))
+	sinLon, cosLon := math.Sincos(radians(ref.Lon))
+
+	return ENU{
+		E: -sinLon*v.X + cosLon*v.Y,
+		N: -sinLat*cosLon*v.X - sinLat*sinLon*v.Y + cosLat*v.Z,
+		U: cosLat*cosLon*v.X + cosLat*sinLon*v.Y + sinLat*v.Z,
+	}
+}
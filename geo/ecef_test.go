@@ -0,0 +1,91 @@
+package geo
+
+import "testing"
+
+func TestToECEF(t *testing.T) {
+	tests := []struct {
+		name   string
+		point  Point
+		altHAE float64
+		want   ECEF
+	}{
+		{"equator, prime meridian", Point{0, 0}, 0, ECEF{SemiMajorAxis, 0, 0}},
+		{"equator, 90E", Point{0, 90}, 100, ECEF{0, SemiMajorAxis + 100, 0}},
+		{"north pole", Point{90, 0}, 0, ECEF{0, 0, SemiMinorAxis}},
+		{"south pole", Point{-90, 0}, 10, ECEF{0, 0, -SemiMinorAxis - 10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ToECEF(tt.point, tt.altHAE)
+			if !near(got.X, tt.want.X, 1e-3) || !near(got.Y, tt.want.Y, 1e-3) || !near(got.Z, tt.want.Z, 1e-3) {
+				t.Errorf("ToECEF = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromECEFRoundTrip(t *testing.T) {
+	for _, lat := range []float64{-90, -89.999, -60, -33.857, -1e-9, 0, 0.5, 45, 60.39135, 84, 89.999, 90} {
+		for _, lon := range []float64{-179.9, -77.0365, 0, 2.2945, 151.215} {
+			for _, alt := range []float64{-100, 0, 1343.127, 20200e3} {
+				p, h := FromECEF(ToECEF(Point{lat, lon}, alt))
+				wantLon := lon
+				if lat == 90 || lat == -90 {
+					wantLon = p.Lon // undefined at the poles
+				}
+				if !near(p.Lat, lat, 1e-9) || !near(p.Lon, wantLon, 1e-9) || !near(h, alt, 1e-4) {
+					t.Errorf("round trip of %v, %v, %v = %+v, %v", lat, lon, alt, p, h)
+				}
+			}
+		}
+	}
+}
+
+func TestENU(t *testing.T) {
+	ref := Point{Lat: 46.498293369, Lon: 7.567411672}
+	tests := []struct {
+		name string
+		e    ECEF
+		want ENU
+	}{
+		{"reference point", ToECEF(ref, 1343), ENU{}},
+		{"straight up", ToECEF(ref, 1443), ENU{0, 0, 100}},
+	}
+	for _, tt := range tests {
+		got := ToENU(tt.e, ref, 1343)
+		if !near(got.E, tt.want.E, 1e-6) || !near(got.N, tt.want.N, 1e-6) || !near(got.U, tt.want.U, 1e-6) {
+			t.Errorf("%s: ToENU = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	// Points a kilometer east and north lie close to the local axes
+	east := ToENU(ToECEF(Destination(ref, 1000, 90), 1343), ref, 1343)
+	if !near(east.E, 1000, 5) || !near(east.N, 0, 5) || !near(east.U, 0, 1) {
+		t.Errorf("1 km east = %+v", east)
+	}
+	north := ToENU(ToECEF(Destination(ref, 1000, 0), 1343), ref, 1343)
+	if !near(north.E, 0, 1e-6) || !near(north.N, 1000, 5) || !near(north.U, 0, 1) {
+		t.Errorf("1 km north = %+v", north)
+	}
+}
+
+func TestRotateENU(t *testing.T) {
+	tests := []struct {
+		ref  Point
+		v    ECEF
+		want ENU
+	}{
+		{Point{0, 0}, ECEF{1, 0, 0}, ENU{0, 0, 1}},
+		{Point{0, 0}, ECEF{0, 1, 0}, ENU{1, 0, 0}},
+		{Point{0, 0}, ECEF{0, 0, 1}, ENU{0, 1, 0}},
+		{Point{90, 0}, ECEF{0, 0, 1}, ENU{0, 0, 1}},
+		{Point{0, 90}, ECEF{-1, 0, 0}, ENU{1, 0, 0}},
+		{Point{45, 0}, ECEF{0, 0, 2}, ENU{0, 1.4142135623730951, 1.4142135623730951}},
+	}
+	for _, tt := range tests {
+		got := RotateENU(tt.v, tt.ref)
+		if !near(got.E, tt.want.E, 1e-12) || !near(got.N, tt.want.N, 1e-12) || !near(got.U, tt.want.U, 1e-12) {
+			t.Errorf("RotateENU(%+v, %+v) = %+v, want %+v", tt.v, tt.ref, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+/*
+* geo.go
+*
+* Geodesy helpers for positions reported by GPSD. Angles are in degrees,
+* distances in meters.
+*
+ */
+
+package geo
+
+import (
+	"errors"
+	"math"
+)
+
+// WGS84 ellipsoid
+const (
+	SemiMajorAxis = 6378137.0                        // Equatorial radius (meters)
+	Flattening    = 1 / 298.257223563                // Flattening of the ellipsoid
+	SemiMinorAxis = SemiMajorAxis * (1 - Flattening) // Polar radius (meters)
+)
+
+// Mean earth radius used by the spherical formulas (meters)
+const EarthRadius = 6371008.8
+
+// Returned by Vincenty when the iteration fails, e.g. for nearly antipodal points
+var ErrNoConvergence = errors.New("geo: Vincenty formula failed to converge")
+
+// Geodetic position on the WGS84 ellipsoid
+type Point struct {
+	Lat float64 // Latitude (degrees)
+	Lon float64 // Longitude (degrees)
+}
+
+func radians(deg float64) float64 { return deg * math.Pi / 180 }
+func degrees(rad float64) float64 { return rad * 180 / math.Pi }
+
+// Normalize an angle in degrees to [0, 360)
+func normalizeBearing(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	return deg
+}
+
+// Normalize a longitude in degrees to [-180, 180)
+func normalizeLon(deg float64) float64 {
+	return normalizeBearing(deg+180) - 180
+}
@@ -0,0 +1,103 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// 100 km square column letters repeat every three zones, row letters
+// every two zones, I and O are never used
+var (
+	mgrsColumns = [3]string{"ABCDEFGH", "JKLMNPQR", "STUVWXYZ"}
+	mgrsRows    = [2]string{"ABCDEFGHJKLMNPQRSTUV", "FGHJKLMNPQRSTUVABCDE"}
+)
+
+// Format a UTM coordinate as an MGRS reference, e.g. "31UDQ4825111932".
+// Precision is the number of digits per axis, from 0 (100 km) to 5 (1 m);
+// coordinates are truncated to it as MGRS requires.
+func FormatMGRS(u UTM, precision int) (string, error) {
+	if precision < 0 || precision > 5 {
+		return "", fmt.Errorf("geo: MGRS precision %d out of range 0-5", precision)
+	}
+	if u.Zone < 1 || u.Zone > 60 || strings.IndexByte(latBands, u.Band) < 0 {
+		return "", ErrInvalidUTM
+	}
+
+	col := int(math.Floor(u.Easting / 100e3))
+	row := int(math.Floor(u.Northing/100e3)) % 20
+	if col < 1 || col > 8 || u.Northing < 0 {
+		return "", ErrInvalidUTM
+	}
+	columns := mgrsColumns[(u.Zone-1)%3]
+	rows := mgrsRows[(u.Zone-1)%2]
+
+	ref := fmt.Sprintf("%d%c%c%c", u.Zone, u.Band, columns[col-1], rows[row])
+	if precision == 0 {
+		return ref, nil
+	}
+	scale := math.Pow10(5 - precision)
+	e := int(math.Mod(u.Easting, 100e3) / scale)
+	n := int(math.Mod(u.Northing, 100e3) / scale)
+	return fmt.Sprintf("%s%0*d%0*d", ref, precision, e, precision, n), nil
+}
+
+// Format a position as an MGRS reference, see FormatMGRS
+func MGRS(p Point, precision int) (string, error) {
+	u, err := ToUTM(p)
+	if err != nil {
+		return "", err
+	}
+	return FormatMGRS(u, precision)
+}
+
+// Parse an MGRS reference such as "31U DQ 48251 11932" or "4QFJ12345678".
+// The result is the south west corner of the referenced square.
+func ParseMGRS(ref string) (UTM, error) {
+	s := strings.ToUpper(strings.Join(strings.Fields(ref), ""))
+
+	i := 0
+	for i < len(s) && i < 2 && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	zone, err := strconv.Atoi(s[:i])
+	if err != nil || zone < 1 || zone > 60 || len(s) < i+3 {
+		return UTM{}, ErrInvalidMGRS
+	}
+	band := s[i]
+	bandIndex := strings.IndexByte(latBands, band)
+	col := strings.IndexByte(mgrsColumns[(zone-1)%3], s[i+1])
+	row := strings.IndexByte(mgrsRows[(zone-1)%2], s[i+2])
+	if bandIndex < 0 || col < 0 || row < 0 {
+		return UTM{}, ErrInvalidMGRS
+	}
+
+	digits := s[i+3:]
+	if len(digits)%2 != 0 || len(digits) > 10 {
+		return UTM{}, ErrInvalidMGRS
+	}
+	precision := len(digits) / 2
+	scale := math.Pow10(5 - precision)
+	var e, n int
+	if precision > 0 {
+		if e, err = strconv.Atoi(digits[:precision]); err != nil {
+			return UTM{}, ErrInvalidMGRS
+		}
+		if n, err = strconv.Atoi(digits[precision:]); err != nil {
+			return UTM{}, ErrInvalidMGRS
+		}
+	}
+
+	easting := float64(col+1)*100e3 + float64(e)*scale
+	northing := float64(row)*100e3 + float64(n)*scale
+
+	// The row letters repeat every 2000 km, lift the northing into the band
+	bandLat := float64(bandIndex-10) * 8
+	bottom := toUTMZone(bandLat, centralMeridian(zone), zone, band).Northing
+	bottom = math.Floor(bottom/100e3) * 100e3
+	for northing < bottom {
+		northing += 2000e3
+	}
+	return UTM{Zone: zone, Band: band, Easting: easting, Northing: northing}, nil
+}
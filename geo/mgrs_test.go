@@ -0,0 +1,66 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMGRS(t *testing.T) {
+	for _, tt := range utmReferences {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MGRS(tt.point, 5)
+			if err != nil || got != tt.mgrs {
+				t.Errorf("MGRS = %q, %v, want %q", got, err, tt.mgrs)
+			}
+
+			// Parsing yields the south west corner of the 1 m square
+			u, err := ParseMGRS(tt.mgrs)
+			want := UTM{tt.utm.Zone, tt.utm.Band, math.Floor(tt.utm.Easting), math.Floor(tt.utm.Northing)}
+			if err != nil || u != want {
+				t.Errorf("ParseMGRS = %+v, %v, want %+v", u, err, want)
+			}
+		})
+	}
+}
+
+func TestFormatMGRSPrecision(t *testing.T) {
+	u := UTM{Zone: 31, Band: 'U', Easting: 448251.898, Northing: 5411943.794}
+	for precision, want := range []string{"31UDQ", "31UDQ41", "31UDQ4811", "31UDQ482119", "31UDQ48251194", "31UDQ4825111943"} {
+		if got, err := FormatMGRS(u, precision); err != nil || got != want {
+			t.Errorf("FormatMGRS precision %d = %q, %v, want %q", precision, got, err, want)
+		}
+	}
+	for _, precision := range []int{-1, 6} {
+		if _, err := FormatMGRS(u, precision); err == nil {
+			t.Errorf("FormatMGRS precision %d succeeded", precision)
+		}
+	}
+	if _, err := MGRS(Point{85, 0}, 5); err != ErrOutsideUTM {
+		t.Errorf("MGRS beyond 84N error = %v, want ErrOutsideUTM", err)
+	}
+}
+
+func TestParseMGRS(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want UTM
+	}{
+		{"31U DQ 48251 11943", UTM{31, 'U', 448251, 5411943}},
+		{"31udq4825111943", UTM{31, 'U', 448251, 5411943}},
+		{"4QFJ12345678", UTM{4, 'Q', 612340, 2356780}},
+		{"04QFJ12345678", UTM{4, 'Q', 612340, 2356780}},
+		{"31UDQ", UTM{31, 'U', 400000, 5400000}},
+		{"56H LH 3 5", UTM{56, 'H', 330000, 6250000}},
+	}
+	for _, tt := range tests {
+		if got, err := ParseMGRS(tt.ref); err != nil || got != tt.want {
+			t.Errorf("ParseMGRS(%q) = %+v, %v, want %+v", tt.ref, got, err, tt.want)
+		}
+	}
+
+	for _, ref := range []string{"", "31", "31U", "31UD", "31UDQ1", "31UDQ12345678901", "0UDQ", "61UDQ", "31IDQ", "31UIQ", "31UDW", "31UDQ12a4"} {
+		if _, err := ParseMGRS(ref); err != ErrInvalidMGRS {
+			t.Errorf("ParseMGRS(%q) error = %v, want ErrInvalidMGRS", ref, err)
+		}
+	}
+}
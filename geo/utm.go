@@ -0,0 +1,213 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	utmScale         = 0.9996  // Scale factor on the central meridian
+	utmFalseEasting  = 500e3   // Easting of the central meridian (meters)
+	utmFalseNorthing = 10000e3 // Northing of the equator in the southern hemisphere (meters)
+	utmMinLat        = -80.0
+	utmMaxLat        = 84.0
+)
+
+// Latitude bands of 8 degrees from 80S, X is stretched to 84N
+const latBands = "CDEFGHJKLMNPQRSTUVWXX"
+
+var (
+	ErrOutsideUTM  = errors.New("geo: latitude outside the UTM limits of 80S to 84N")
+	ErrInvalidUTM  = errors.New("geo: invalid UTM coordinate")
+	ErrInvalidMGRS = errors.New("geo: invalid MGRS reference")
+)
+
+// Universal Transverse Mercator coordinate
+type UTM struct {
+	Zone     int     // Longitude zone (1-60)
+	Band     byte    // Latitude band (C-X), N and above are in the northern hemisphere
+	Easting  float64 // Meters from the false origin west of the central meridian
+	Northing float64 // Meters from the equator, or from 10000 km south of it below the equator
+}
+
+// Report whether the coordinate lies in the northern hemisphere
+func (u UTM) North() bool {
+	return u.Band >= 'N'
+}
+
+func (u UTM) String() string {
+	return fmt.Sprintf("%d%c %.0f %.0f", u.Zone, u.Band, u.Easting, u.Northing)
+}
+
+// Latitude band letter of a latitude within the UTM limits
+func latBand(lat float64) byte {
+	return latBands[int(math.Floor(lat/8+10))]
+}
+
+// Zone of a position, including the Norway and Svalbard exceptions
+func utmZone(lat, lon float64, band byte) int {
+	zone := int(math.Floor((lon+180)/6)) + 1
+	zone = min(max(zone, 1), 60)
+
+	switch {
+	case band == 'V' && zone == 31 && lon >= 3: // south west Norway
+		zone = 32
+	case band == 'X' && zone == 32: // Svalbard has no zones 32, 34 or 36
+		zone = 31 + 2*boolInt(lon >= 9)
+	case band == 'X' && zone == 34:
+		zone = 33 + 2*boolInt(lon >= 21)
+	case band == 'X' && zone == 36:
+		zone = 35 + 2*boolInt(lon >= 33)
+	}
+	return zone
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Convert a position to UTM in its standard zone
+func ToUTM(p Point) (UTM, error) {
+	if p.Lat < utmMinLat || p.Lat > utmMaxLat {
+		return UTM{}, ErrOutsideUTM
+	}
+	lon := normalizeLon(p.Lon)
+	band := latBand(p.Lat)
+	return toUTMZone(p.Lat, lon, utmZone(p.Lat, lon, band), band), nil
+}
+
+// Convert a position to UTM in the given zone, e.g. to keep a survey
+// spanning a zone boundary on a single grid
+func ToUTMZone(p Point, zone int) (UTM, error) {
+	if p.Lat < utmMinLat || p.Lat > utmMaxLat {
+		return UTM{}, ErrOutsideUTM
+	}
+	if zone < 1 || zone > 60 {
+		return UTM{}, ErrInvalidUTM
+	}
+	return toUTMZone(p.Lat, normalizeLon(p.Lon), zone, latBand(p.Lat)), nil
+}
+
+// Transverse Mercator projection using Krüger's series to sixth order in n
+func toUTMZone(lat, lon float64, zone int, band byte) UTM {
+	phi := radians(lat)
+	lambda := radians(lon - centralMeridian(zone))
+	ecc := math.Sqrt(eccSq)
+
+	sinLambda, cosLambda := math.Sincos(lambda)
+	tau := math.Tan(phi)
+	sigma := math.Sinh(ecc * math.Atanh(ecc*tau/math.Sqrt(1+tau*tau)))
+	tauP := tau*math.Sqrt(1+sigma*sigma) - sigma*math.Sqrt(1+tau*tau)
+
+	xiP := math.Atan2(tauP, cosLambda)
+	etaP := math.Asinh(sinLambda / math.Sqrt(tauP*tauP+cosLambda*cosLambda))
+
+	xi, eta := xiP, etaP
+	for j, alpha := range krugerAlpha {
+		k := 2 * float64(j+1)
+		xi += alpha * math.Sin(k*xiP) * math.Cosh(k*etaP)
+		eta += alpha * math.Cos(k*xiP) * math.Sinh(k*etaP)
+	}
+
+	northing := utmScale * rectifyingRadius * xi
+	if lat < 0 {
+		northing += utmFalseNorthing
+	}
+	return UTM{
+		Zone:     zone,
+		Band:     band,
+		Easting:  utmScale*rectifyingRadius*eta + utmFalseEasting,
+		Northing: northing,
+	}
+}
+
+// Convert a UTM coordinate back to a position
+func (u UTM) Point() (Point, error) {
+	if u.Zone < 1 || u.Zone > 60 || u.Band < 'C' || u.Band > 'X' || u.Band == 'I' || u.Band == 'O' {
+		return Point{}, ErrInvalidUTM
+	}
+
+	y := u.Northing
+	if !u.North() {
+		y -= utmFalseNorthing
+	}
+	xi := y / (utmScale * rectifyingRadius)
+	eta := (u.Easting - utmFalseEasting) / (utmScale * rectifyingRadius)
+
+	xiP, etaP := xi, eta
+	for j, beta := range krugerBeta {
+		k := 2 * float64(j+1)
+		xiP -= beta * math.Sin(k*xi) * math.Cosh(k*eta)
+		etaP -= beta * math.Cos(k*xi) * math.Sinh(k*eta)
+	}
+
+	sinhEtaP := math.Sinh(etaP)
+	sinXiP, cosXiP := math.Sincos(xiP)
+	tauP := sinXiP / math.Sqrt(sinhEtaP*sinhEtaP+cosXiP*cosXiP)
+
+	// Newton-Raphson on tau, converges in a handful of iterations
+	ecc := math.Sqrt(eccSq)
+	tau := tauP
+	for range 10 {
+		sigma := math.Sinh(ecc * math.Atanh(ecc*tau/math.Sqrt(1+tau*tau)))
+		tauI := tau*math.Sqrt(1+sigma*sigma) - sigma*math.Sqrt(1+tau*tau)
+		delta := (tauP - tauI) / math.Sqrt(1+tauI*tauI) *
+			(1 + (1-eccSq)*tau*tau) / ((1 - eccSq) * math.Sqrt(1+tau*tau))
+		tau += delta
+		if math.Abs(delta) < 1e-12 {
+			break
+		}
+	}
+
+	lon := degrees(math.Atan2(sinhEtaP, cosXiP)) + centralMeridian(u.Zone)
+	return Point{Lat: degrees(math.Atan(tau)), Lon: normalizeLon(lon)}, nil
+}
+
+// Longitude of the central meridian of a zone (degrees)
+func centralMeridian(zone int) float64 {
+	return float64(zone-1)*6 - 180 + 3
+}
+
+// Series coefficients in the third flattening n
+var (
+	thirdFlattening  = Flattening / (2 - Flattening)
+	rectifyingRadius = rectifying(thirdFlattening)
+	krugerAlpha      = alphaSeries(thirdFlattening)
+	krugerBeta       = betaSeries(thirdFlattening)
+)
+
+// Radius of the sphere with the same meridian length as the ellipsoid
+func rectifying(n float64) float64 {
+	n2 := n * n
+	return SemiMajorAxis / (1 + n) * (1 + n2/4 + n2*n2/64 + n2*n2*n2/256)
+}
+
+// Coefficients of the forward projection
+func alphaSeries(n float64) [6]float64 {
+	n2, n3, n4, n5, n6 := n*n, n*n*n, n*n*n*n, n*n*n*n*n, n*n*n*n*n*n
+	return [6]float64{
+		n/2 - 2*n2/3 + 5*n3/16 + 41*n4/180 - 127*n5/288 + 7891*n6/37800,
+		13*n2/48 - 3*n3/5 + 557*n4/1440 + 281*n5/630 - 1983433*n6/1935360,
+		61*n3/240 - 103*n4/140 + 15061*n5/26880 + 167603*n6/181440,
+		49561*n4/161280 - 179*n5/168 + 6601661*n6/7257600,
+		34729*n5/80640 - 3418889*n6/1995840,
+		212378941 * n6 / 319334400,
+	}
+}
+
+// Coefficients of the inverse projection
+func betaSeries(n float64) [6]float64 {
+	n2, n3, n4, n5, n6 := n*n, n*n*n, n*n*n*n, n*n*n*n*n, n*n*n*n*n*n
+	return [6]float64{
+		n/2 - 2*n2/3 + 37*n3/96 - n4/360 - 81*n5/512 + 96199*n6/604800,
+		n2/48 + n3/15 - 437*n4/1440 + 46*n5/105 - 1118711*n6/3870720,
+		17*n3/480 - 37*n4/840 - 209*n5/4480 + 5569*n6/90720,
+		4397*n4/161280 - 11*n5/504 - 830251*n6/7257600,
+		4583*n5/161280 - 108847*n6/3991680,
+		20648693 * n6 / 638668800,
+	}
+}
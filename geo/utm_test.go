@@ -0,0 +1,99 @@
+package geo
+
+import "testing"
+
+// Reference coordinates as published for well known landmarks
+var utmReferences = []struct {
+	name  string
+	point Point
+	utm   UTM
+	mgrs  string
+}{
+	{"null island", Point{0, 0}, UTM{31, 'N', 166021.443, 0}, "31NAA6602100000"},
+	{"one degree north east", Point{1, 1}, UTM{31, 'N', 277438.263, 110597.973}, "31NBB7743810597"},
+	{"one degree south west", Point{-1, -1}, UTM{30, 'M', 722561.737, 9889402.027}, "30MYD2256189402"},
+	{"Eiffel Tower", Point{48.8583, 2.2945}, UTM{31, 'U', 448251.898, 5411943.794}, "31UDQ4825111943"},
+	{"Sydney Opera House", Point{-33.857, 151.215}, UTM{56, 'H', 334873.199, 6252266.092}, "56HLH3487352266"},
+	{"White House", Point{38.8977, -77.0365}, UTM{18, 'S', 323394.296, 4307395.634}, "18SUJ2339407395"},
+	{"Christ the Redeemer", Point{-22.9519, -43.2106}, UTM{23, 'K', 683466.254, 7460687.433}, "23KPQ8346660687"},
+	{"Bergen, in zone 32V", Point{60.39135, 5.3249}, UTM{32, 'V', 297508.410, 6700645.296}, "32VKN9750800645"},
+}
+
+func TestToUTM(t *testing.T) {
+	for _, tt := range utmReferences {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToUTM(tt.point)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Zone != tt.utm.Zone || got.Band != tt.utm.Band ||
+				!near(got.Easting, tt.utm.Easting, 0.001) || !near(got.Northing, tt.utm.Northing, 0.001) {
+				t.Errorf("ToUTM = %d%c %.3f %.3f, want %d%c %.3f %.3f", got.Zone, got.Band, got.Easting, got.Northing,
+					tt.utm.Zone, tt.utm.Band, tt.utm.Easting, tt.utm.Northing)
+			}
+
+			back, err := got.Point()
+			if err != nil || !near(back.Lat, tt.point.Lat, 1e-9) || !near(back.Lon, tt.point.Lon, 1e-9) {
+				t.Errorf("round trip = %+v, %v, want %+v", back, err, tt.point)
+			}
+		})
+	}
+}
+
+func TestUTMZoneExceptions(t *testing.T) {
+	tests := []struct {
+		point Point
+		zone  int
+	}{
+		{Point{60, 2.9}, 31},   // west of the Norway extension
+		{Point{60, 3.1}, 32},   // south west Norway is in 32V
+		{Point{55.9, 3.1}, 31}, // band U is not extended
+		{Point{65, 3.1}, 31},   // band W is not extended
+		{Point{78, 8.9}, 31},   // Svalbard, zone 32X does not exist
+		{Point{78, 9.1}, 33},
+		{Point{78, 20.9}, 33}, // zone 34X does not exist
+		{Point{78, 21.1}, 35},
+		{Point{78, 32.9}, 35}, // zone 36X does not exist
+		{Point{78, 33.1}, 37},
+		{Point{71.9, 9.1}, 32}, // band W is not merged
+		{Point{0, 179.9}, 60},
+		{Point{0, 180}, 1}, // the antimeridian normalizes to -180
+		{Point{0, -180}, 1},
+	}
+	for _, tt := range tests {
+		u, err := ToUTM(tt.point)
+		if err != nil || u.Zone != tt.zone {
+			t.Errorf("ToUTM(%+v) zone %d, %v, want %d", tt.point, u.Zone, err, tt.zone)
+			continue
+		}
+		back, err := u.Point()
+		if err != nil || !near(back.Lat, tt.point.Lat, 1e-9) || !near(normalizeLon(back.Lon-tt.point.Lon), 0, 1e-9) {
+			t.Errorf("round trip of %+v = %+v, %v", tt.point, back, err)
+		}
+	}
+}
+
+func TestUTMLimits(t *testing.T) {
+	for _, p := range []Point{{-80.1, 0}, {84.1, 0}, {90, 0}} {
+		if _, err := ToUTM(p); err != ErrOutsideUTM {
+			t.Errorf("ToUTM(%+v) error = %v, want ErrOutsideUTM", p, err)
+		}
+	}
+	if _, err := ToUTMZone(Point{10, 10}, 61); err != ErrInvalidUTM {
+		t.Errorf("ToUTMZone zone 61 error = %v, want ErrInvalidUTM", err)
+	}
+	for _, u := range []UTM{{0, 'N', 500e3, 0}, {31, 'I', 500e3, 0}, {31, 'Y', 500e3, 0}} {
+		if _, err := u.Point(); err != ErrInvalidUTM {
+			t.Errorf("%+v.Point() error = %v, want ErrInvalidUTM", u, err)
+		}
+	}
+
+	// A forced neighbouring zone still converts back to the same position
+	u, err := ToUTMZone(Point{48.8583, 2.2945}, 30)
+	if err != nil || u.Zone != 30 {
+		t.Fatalf("ToUTMZone = %+v, %v", u, err)
+	}
+	if back, _ := u.Point(); !near(back.Lat, 48.8583, 1e-9) || !near(back.Lon, 2.2945, 1e-9) {
+		t.Errorf("round trip through zone 30 = %+v", back)
+	}
+}
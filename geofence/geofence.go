@@ -0,0 +1,248 @@
+/*
+* geofence.go
+*
+* Enter, exit and dwell events for fences evaluated against the TPV
+* stream of a Session
+*
+ */
+
+package geofence
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/AryaanSheth/gopsd"
+)
+
+// Event kind constants
+const (
+	Enter EventKind = iota // Fix moved inside the fence
+	Exit                   // Fix moved outside the fence
+	Dwell                  // Fix stayed inside the fence for its Dwell time
+)
+
+// Defaults for the boundary margin
+const (
+	DefaultConfidence = 2.0 // GST standard deviations, about 95%
+	DefaultMinMargin  = 0.0 // Meters
+)
+
+type EventKind byte // Kind of fence transition
+
+// Named area watched by a Monitor
+type Fence struct {
+	ID    string        // Unique name, adding a fence with the same ID replaces it
+	Shape Shape         // Area covered by the fence
+	Dwell time.Duration // Time inside before a Dwell event, zero disables it
+}
+
+// Fence transition of a device
+type Event struct {
+	Kind   EventKind
+	Fence  *Fence
+	Device string    // Device the fix came from
+	Time   time.Time // Time of the fix, or of arrival when GPSD sent none
+	TPV    gopsd.TPV // Fix that triggered the event
+}
+
+type Option func(*Monitor) // Monitor option
+
+// Fence state of a device
+type presence byte
+
+const (
+	unknown presence = iota
+	inside
+	outside
+)
+
+type stateKey struct {
+	device string
+	fence  string
+}
+
+type fenceState struct {
+	presence presence
+	since    time.Time // Time of the Enter transition
+	dwelled  bool      // Dwell event sent for the current visit
+}
+
+// Evaluates fences against the TPV stream of a Session
+type Monitor struct {
+	fn         func(Event)
+	confidence float64
+	minMargin  float64
+	handles    []*gopsd.Handle
+
+	mu     sync.Mutex
+	fences []*Fence
+	states map[stateKey]*fenceState
+	errors map[string]float64 // Latest GST semi-major axis deviation by device
+}
+
+// Scale the GST error ellipse by k standard deviations when widening the boundary
+func WithConfidence(k float64) Option {
+	return func(m *Monitor) { m.confidence = k }
+}
+
+// Never let the boundary margin drop below meters, hiding small jitter
+// of receivers that report optimistic errors
+func WithMinMargin(meters float64) Option {
+	return func(m *Monitor) { m.minMargin = meters }
+}
+
+// Start evaluating fences against every TPV of the session, calling fn
+// on the reader goroutine for each event. A fix only counts as inside or
+// outside once it is beyond the boundary by more than its horizontal
+// error: the GST semi-major axis scaled by the confidence when the device
+// sends GST, otherwise Eph. This keeps a fix near the boundary from flapping.
+func Watch(s *gopsd.Session, fn func(Event), opts ...Option) *Monitor {
+	m := New(fn, opts...)
+	m.handles = append(m.handles,
+		gopsd.On(s, m.Update),
+		gopsd.On(s, m.updateError),
+	)
+	return m
+}
+
+// Create a Monitor fed by calling Update, e.g. when replaying a log
+func New(fn func(Event), opts ...Option) *Monitor {
+	m := &Monitor{
+		fn:         fn,
+		confidence: DefaultConfidence,
+		minMargin:  DefaultMinMargin,
+		states:     make(map[stateKey]*fenceState),
+		errors:     make(map[string]float64),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Stop receiving reports from the session
+func (m *Monitor) Stop() {
+	for _, h := range m.handles {
+		h.Unsubscribe()
+	}
+}
+
+// Add fences, replacing those with the same ID
+func (m *Monitor) Add(fences ...*Fence) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, f := range fences {
+		m.remove(f.ID)
+		m.fences = append(m.fences, f)
+	}
+}
+
+// Remove a fence without sending Exit events
+func (m *Monitor) Remove(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.remove(id)
+}
+
+func (m *Monitor) remove(id string) {
+	for i, f := range m.fences {
+		if f.ID == id {
+			m.fences = append(m.fences[:i:i], m.fences[i+1:]...)
+			break
+		}
+	}
+	for key := range m.states {
+		if key.fence == id {
+			delete(m.states, key)
+		}
+	}
+}
+
+// Fences currently held by the monitor
+func (m *Monitor) Fences() []*Fence {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*Fence(nil), m.fences...)
+}
+
+// Evaluate a fix against every fence. Fixes without a horizontal position are ignored.
+func (m *Monitor) Update(tpv *gopsd.TPV) {
+	if tpv.Mode < gopsd.Mode2D {
+		return
+	}
+	at := tpv.Time
+	if at.IsZero() {
+		at = time.Now()
+	}
+	p := tpv.Point()
+
+	var events []Event
+	m.mu.Lock()
+	margin := m.margin(tpv)
+	for _, f := range m.fences {
+		key := stateKey{device: tpv.Device, fence: f.ID}
+		st := m.states[key]
+		if st == nil {
+			st = &fenceState{}
+			m.states[key] = st
+		}
+
+		d := f.Shape.Distance(p)
+		switch {
+		case d < -margin && st.presence != inside:
+			*st = fenceState{presence: inside, since: at}
+			events = append(events, Event{Kind: Enter, Fence: f})
+		case d > margin && st.presence != outside:
+			if st.presence == inside {
+				events = append(events, Event{Kind: Exit, Fence: f})
+			}
+			*st = fenceState{presence: outside}
+		}
+
+		if st.presence == inside && f.Dwell > 0 && !st.dwelled && at.Sub(st.since) >= f.Dwell {
+			st.dwelled = true
+			events = append(events, Event{Kind: Dwell, Fence: f})
+		}
+	}
+	m.mu.Unlock()
+
+	for _, e := range events {
+		e.Device, e.Time, e.TPV = tpv.Device, at, *tpv
+		m.fn(e)
+	}
+}
+
+// Record the error ellipse of a device
+func (m *Monitor) updateError(gst *gopsd.GST) {
+	if gst.Major <= 0 {
+		return
+	}
+	m.mu.Lock()
+	m.errors[gst.Device] = math.Max(gst.Major, gst.Minor)
+	m.mu.Unlock()
+}
+
+// Distance a fix must be past the boundary to count as inside or outside
+func (m *Monitor) margin(tpv *gopsd.TPV) float64 {
+	if major, ok := m.errors[tpv.Device]; ok {
+		return max(m.minMargin, m.confidence*major)
+	}
+	if tpv.Eph > 0 {
+		return max(m.minMargin, tpv.Eph)
+	}
+	return m.minMargin
+}
+
+func (k EventKind) String() string {
+	switch k {
+	case Enter:
+		return "enter"
+	case Exit:
+		return "exit"
+	case Dwell:
+		return "dwell"
+	}
+	return "unknown"
+}
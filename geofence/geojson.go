@@ -0,0 +1,163 @@
+package geofence
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/AryaanSheth/gopsd/geo"
+)
+
+var ErrUnsupportedGeometry = errors.New("geofence: unsupported GeoJSON geometry")
+
+type geoJSON struct {
+	Type        string          `json:"type"`
+	ID          json.RawMessage `json:"id"`
+	Features    []geoJSON       `json:"features"`
+	Geometry    *geoJSON        `json:"geometry"`
+	Coordinates json.RawMessage `json:"coordinates"`
+	Properties  struct {
+		Name   string  `json:"name"`
+		Radius float64 `json:"radius"` // Circle radius for Point features (meters)
+		Buffer float64 `json:"buffer"` // Corridor half width for LineString features (meters)
+		Dwell  float64 `json:"dwell"`  // Dwell time (seconds)
+	} `json:"properties"`
+}
+
+// Read fences from a GeoJSON Feature or FeatureCollection. Supported geometries:
+//
+//	Polygon      outer ring followed by holes
+//	Point        circle, requires a "radius" property in meters
+//	LineString   corridor, requires a "buffer" property in meters
+//
+// The fence ID is the feature id, else its "name" property, else its index.
+// An optional "dwell" property sets the dwell time in seconds.
+func ParseGeoJSON(data []byte) ([]*Fence, error) {
+	var doc geoJSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("geofence: %w", err)
+	}
+
+	features := []geoJSON{doc}
+	if doc.Type == "FeatureCollection" {
+		features = doc.Features
+	}
+	fences := make([]*Fence, 0, len(features))
+	for i, feature := range features {
+		f, err := parseFeature(feature, i)
+		if err != nil {
+			return nil, err
+		}
+		fences = append(fences, f)
+	}
+	return fences, nil
+}
+
+func parseFeature(feature geoJSON, index int) (*Fence, error) {
+	if feature.Type != "Feature" || feature.Geometry == nil {
+		return nil, fmt.Errorf("geofence: feature %d: expected a Feature with a geometry", index)
+	}
+	f := &Fence{
+		ID:    featureID(feature, index),
+		Dwell: time.Duration(feature.Properties.Dwell * float64(time.Second)),
+	}
+
+	var err error
+	geometry := feature.Geometry
+	switch geometry.Type {
+	case "Polygon":
+		var rings [][][]float64
+		if err = json.Unmarshal(geometry.Coordinates, &rings); err != nil {
+			break
+		}
+		if len(rings) == 0 {
+			err = errors.New("polygon without rings")
+			break
+		}
+		var polygon Polygon
+		for i, ring := range rings {
+			points, perr := toPoints(ring)
+			if perr != nil {
+				err = perr
+				break
+			}
+			if i == 0 {
+				polygon.Outer = points
+			} else {
+				polygon.Holes = append(polygon.Holes, points)
+			}
+		}
+		f.Shape = polygon
+	case "Point":
+		var position []float64
+		if err = json.Unmarshal(geometry.Coordinates, &position); err != nil {
+			break
+		}
+		if feature.Properties.Radius <= 0 {
+			err = errors.New("point without a positive radius property")
+			break
+		}
+		center, perr := toPoint(position)
+		err = perr
+		f.Shape = Circle{Center: center, Radius: feature.Properties.Radius}
+	case "LineString":
+		var line [][]float64
+		if err = json.Unmarshal(geometry.Coordinates, &line); err != nil {
+			break
+		}
+		if feature.Properties.Buffer <= 0 {
+			err = errors.New("line string without a positive buffer property")
+			break
+		}
+		path, perr := toPoints(line)
+		err = perr
+		f.Shape = Corridor{Path: path, Buffer: feature.Properties.Buffer}
+	default:
+		return nil, fmt.Errorf("%w %q in feature %q", ErrUnsupportedGeometry, geometry.Type, f.ID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("geofence: feature %q: %w", f.ID, err)
+	}
+	return f, nil
+}
+
+// Feature id as a string, falling back to the name property and the index
+func featureID(feature geoJSON, index int) string {
+	var id string
+	if err := json.Unmarshal(feature.ID, &id); err == nil && id != "" {
+		return id
+	}
+	var n json.Number
+	if err := json.Unmarshal(feature.ID, &n); err == nil {
+		return n.String()
+	}
+	if feature.Properties.Name != "" {
+		return feature.Properties.Name
+	}
+	return strconv.Itoa(index)
+}
+
+// GeoJSON positions are longitude first
+func toPoint(position []float64) (geo.Point, error) {
+	if len(position) < 2 {
+		return geo.Point{}, errors.New("position needs longitude and latitude")
+	}
+	return geo.Point{Lat: position[1], Lon: position[0]}, nil
+}
+
+func toPoints(positions [][]float64) ([]geo.Point, error) {
+	if len(positions) == 0 {
+		return nil, errors.New("empty coordinates")
+	}
+	points := make([]geo.Point, 0, len(positions))
+	for _, position := range positions {
+		p, err := toPoint(position)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
@@ -0,0 +1,109 @@
+package geofence
+
+import (
+	"math"
+
+	"github.com/AryaanSheth/gopsd/geo"
+)
+
+// Area a fence covers
+type Shape interface {
+	// Signed distance in meters from p to the boundary, negative inside
+	Distance(p geo.Point) float64
+}
+
+// Polygon with optional holes, rings need not be closed
+type Polygon struct {
+	Outer []geo.Point   // Outer boundary
+	Holes [][]geo.Point // Excluded areas inside the outer boundary
+}
+
+// Circle of Radius meters around Center
+type Circle struct {
+	Center geo.Point
+	Radius float64 // Meters
+}
+
+// Corridor of Buffer meters either side of a path, e.g. a route or a road
+type Corridor struct {
+	Path   []geo.Point
+	Buffer float64 // Meters from the center line
+}
+
+func (c Circle) Distance(p geo.Point) float64 {
+	return geo.Distance(c.Center, p) - c.Radius
+}
+
+func (c Corridor) Distance(p geo.Point) float64 {
+	plane := newPlane(p)
+	d := math.Inf(1)
+	if len(c.Path) == 1 {
+		d = math.Hypot(plane.project(c.Path[0]))
+	}
+	for i := 1; i < len(c.Path); i++ {
+		d = min(d, plane.segmentDistance(c.Path[i-1], c.Path[i]))
+	}
+	return d - c.Buffer
+}
+
+func (pg Polygon) Distance(p geo.Point) float64 {
+	plane := newPlane(p)
+	d, inside := plane.ring(pg.Outer)
+	for _, hole := range pg.Holes {
+		dh, inHole := plane.ring(hole)
+		d = min(d, dh)
+		inside = inside && !inHole
+	}
+	if inside {
+		return -d
+	}
+	return d
+}
+
+// Local tangent plane centered on a point, accurate enough for fences
+// spanning up to some tens of kilometers
+type plane struct {
+	origin geo.Point
+	scaleX float64 // Meters per degree of longitude
+	scaleY float64 // Meters per degree of latitude
+}
+
+func newPlane(origin geo.Point) plane {
+	scaleY := geo.EarthRadius * math.Pi / 180
+	return plane{origin: origin, scaleX: scaleY * math.Cos(origin.Lat*math.Pi/180), scaleY: scaleY}
+}
+
+// Coordinates of q in meters east and north of the origin
+func (pl plane) project(q geo.Point) (x, y float64) {
+	dLon := math.Mod(q.Lon-pl.origin.Lon+540, 360) - 180
+	return dLon * pl.scaleX, (q.Lat - pl.origin.Lat) * pl.scaleY
+}
+
+// Distance from the origin to the segment a-b
+func (pl plane) segmentDistance(a, b geo.Point) float64 {
+	ax, ay := pl.project(a)
+	bx, by := pl.project(b)
+	dx, dy := bx-ax, by-ay
+	t := 0.0
+	if l := dx*dx + dy*dy; l > 0 {
+		t = max(0, min(1, -(ax*dx+ay*dy)/l))
+	}
+	return math.Hypot(ax+t*dx, ay+t*dy)
+}
+
+// Distance from the origin to a ring and whether the ring contains it
+func (pl plane) ring(ring []geo.Point) (float64, bool) {
+	d, inside := math.Inf(1), false
+	for i := range ring {
+		a, b := ring[i], ring[(i+1)%len(ring)]
+		d = min(d, pl.segmentDistance(a, b))
+
+		// Cast a ray along the positive x axis and count crossings
+		ax, ay := pl.project(a)
+		bx, by := pl.project(b)
+		if (ay > 0) != (by > 0) && ax+(bx-ax)*(-ay)/(by-ay) > 0 {
+			inside = !inside
+		}
+	}
+	return d, inside
+}
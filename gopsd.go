@@ -2,149 +2,565 @@ package gopsd
 
 import (
 	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net"
-	"sync"
 	"time"
-
-	"github.com/bytedance/sonic"
 )
 
 const (
-	syscallBufferSize = 4096
-	connectionType    = "tcp4"
+	syscallBufferSize  = 4096
+	defaultMaxLineSize = 64 << 10
+	maxErrorLine       = 256 // Prefix of an oversized line kept for its error
 )
 
-// Open a new connection to the GPSD daemon
-func Dial(address string) (*Session, error) {
-	return dialCommon(net.Dial(connectionType, address))
+// Open a new connection to the GPSD daemon.
+// The address is host:port, a Unix socket path or a tcp://, tcp4://,
+// tcp6://, unix:// or gpsd://host:port/device URL.
+func Dial(address string, opts ...Option) (*Session, error) {
+	return dialCommon(context.Background(), address, 0, opts)
 }
 
 // Open a new connection to GPSD with timeout
-func DialTimeout(address string, to time.Duration) (*Session, error) {
-	return dialCommon(net.DialTimeout(connectionType, address, to))
+func DialTimeout(address string, to time.Duration, opts ...Option) (*Session, error) {
+	return dialCommon(context.Background(), address, to, opts)
+}
+
+// Open a new connection to GPSD, aborting if ctx is cancelled first
+func DialContext(ctx context.Context, address string, opts ...Option) (*Session, error) {
+	return dialCommon(ctx, address, 0, opts)
 }
 
 // Create a new session with a connection and reader
-func dialCommon(c net.Conn, err error) (*Session, error) {
+func dialCommon(ctx context.Context, address string, to time.Duration, opts []Option) (*Session, error) {
+	ep, err := parseAddress(address)
 	if err != nil {
 		return nil, err
 	}
 
 	session := &Session{
-		conn:    c,
-		reader:  bufio.NewReaderSize(c, syscallBufferSize),
-		filters: sync.Map{},
+		endpoint: ep,
+		timeout:  to,
+		decoder:  defaultDecoder,
+		maxLine:  defaultMaxLineSize,
+		quit:     make(chan struct{}),
+		done:     make(chan bool, 1),
+		filters:  map[string][]*filterEntry{},
+	}
+	for _, opt := range opts {
+		opt(session)
 	}
 
-	// Read initial connection message without alloc
-	_, _ = session.reader.ReadString('\n')
+	session.setState(StateConnecting)
+	if err := session.connect(ctx); err != nil {
+		return nil, err
+	}
 
 	return session, nil
 }
 
-// GPSD watcher session for checking reports
+// Dial the GPSD daemon and swap in the new connection
+func (s *Session) connect(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: s.timeout}
+	c, err := dialer.DialContext(ctx, s.endpoint.network, s.endpoint.address)
+	if err != nil {
+		return err
+	}
+	reader := bufio.NewReaderSize(c, syscallBufferSize)
+
+	// Read the VERSION banner sent on connect
+	stop := context.AfterFunc(ctx, func() { _ = c.SetReadDeadline(time.Unix(1, 0)) })
+	version, err := s.readVersion(reader)
+	if !stop() {
+		_ = c.Close()
+		return ctx.Err()
+	}
+	if err != nil {
+		_ = c.Close()
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		_ = c.Close()
+		return ErrClosed
+	}
+	if s.conn != nil {
+		_ = s.conn.Close() // the dropped connection being replaced
+	}
+	s.conn, s.reader, s.version = c, reader, version
+	return nil
+}
+
+// Parse the banner and check it against the minimum protocol version
+func (s *Session) readVersion(reader *bufio.Reader) (*VERSION, error) {
+	line, err := reader.ReadSlice('\n')
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading banner: %w", ErrConnectionLost, err)
+	}
+
+	var version VERSION
+	if err := s.decoder.Unmarshal(line, &version); err != nil {
+		return nil, newProtocolError(ErrMalformedJSON, line, err)
+	}
+	if version.Class != "VERSION" {
+		return nil, newProtocolError(ErrMalformedJSON, line, errors.New("banner is not a VERSION report"))
+	}
+
+	if !version.AtLeast(s.minProto[0], s.minProto[1]) {
+		return nil, fmt.Errorf("%w: GPSD %s speaks protocol %d.%d, %d.%d required",
+			ErrProtocol, version.Release, version.ProtoMajor, version.ProtoMinor, s.minProto[0], s.minProto[1])
+	}
+	return &version, nil
+}
+
+// VERSION banner of the current connection
+func (s *Session) Version() *VERSION {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.version
+}
+
+// Fail Dial unless GPSD speaks at least protocol major.minor
+func WithMinProtocol(major, minor int) Option {
+	return func(s *Session) { s.minProto = [2]int{major, minor} }
+}
+
+// Report whether the protocol version is at least major.minor
+func (v *VERSION) AtLeast(major, minor int) bool {
+	if v.ProtoMajor != major {
+		return v.ProtoMajor > major
+	}
+	return v.ProtoMinor >= minor
+}
+
+// GPSD watcher session for checking reports. The channel is signalled
+// when watching stops, Err explains why.
 func (s *Session) Watch() <-chan bool {
-	_, _ = s.conn.Write([]byte(`?WATCH={"enable":true,"json":true}`))
+	if err := s.startWatch(context.Background(), defaultWatch()); err != nil {
+		s.mu.Lock()
+		s.err = err
+		s.mu.Unlock()
+		s.reportError(err)
 
-	done := make(chan bool, 1)
-	go s.watchReports(done)
-	return done
+		failed := make(chan bool, 1)
+		failed <- true
+		return failed
+	}
+	return s.done
 }
 
-// Send a command to GPSD
-func (s *Session) SendCommand(command string) {
-	_, _ = s.conn.Write([]byte("?" + command + ";"))
+// Watch reports until ctx is cancelled or the connection is lost.
+// Cancelling ctx closes the session and returns ctx.Err().
+func (s *Session) WatchContext(ctx context.Context) error {
+	if err := s.startWatch(ctx, defaultWatch()); err != nil {
+		return err
+	}
+
+	select {
+	case <-s.done:
+		return s.Err()
+	case <-ctx.Done():
+		_ = s.Close()
+		<-s.done
+		return ctx.Err()
+	}
 }
 
-// Attach a filter to a class of reports
-func (s *Session) AddFilter(class string, f Filter) {
-	filters, _ := s.filters.LoadOrStore(class, []Filter{})
-	s.filters.Store(class, append(filters.([]Filter), f))
+// Apply a WATCH policy and wait for GPSD to echo the settings in effect.
+// Calling it again on a live session changes the policy, the latest one
+// GPSD accepted is replayed after a reconnect. Class is filled in automatically.
+func (s *Session) WatchWith(ctx context.Context, w WATCH) (*WATCH, error) {
+	watch, err := s.watchCommand(w)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := s.request(ctx, watch, "WATCH", nil)
+	if err != nil {
+		return nil, err
+	}
+	s.setWatch(watch) // replay only a policy GPSD accepted
+	return reply.(*WATCH), nil
 }
 
-// Safely close the GPSD connection
-func (s *Session) Close() error {
-	if s.conn == nil {
-		return errors.New("GPSD socket is already closed")
+// Channel signalled once the report reader stops
+func (s *Session) Done() <-chan bool {
+	return s.done
+}
+
+// Send a WATCH policy and spawn the report reader
+func (s *Session) startWatch(ctx context.Context, w WATCH) error {
+	watch, err := s.watchCommand(w)
+	if err != nil {
+		return err
 	}
-	return s.conn.Close()
+	s.setWatch(watch)
+
+	if err := s.write(ctx, watch); err != nil {
+		return err
+	}
+	s.startReader(ctx)
+	return nil
 }
 
-// Handle report watching and dispatching
-func (s *Session) watchReports(done chan<- bool) {
-	defer func() { done <- true }()
+// Spawn the report reader unless it is already running
+func (s *Session) startReader(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.reading {
+		s.reading = true
+		go s.watchReports(ctx, s.done)
+	}
+}
 
-	scanner := bufio.NewScanner(s.reader)
-	scanner.Buffer(make([]byte, syscallBufferSize), syscallBufferSize*10)
+// Remember the WATCH command to replay on reconnect
+func (s *Session) setWatch(watch []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.watch = watch
+}
 
-	for scanner.Scan() {
-		lineBytes := scanner.Bytes()
+// Leanest WATCH policy serving the current subscribers: JSON without
+// NMEA or raw dumps, PPS and TOFF only when subscribed, restricted to the
+// device named in the Dial address. GPSD cannot filter other classes, those
+// are dropped undecoded on arrival when nobody subscribed to them.
+func (s *Session) SubscribedWatch() WATCH {
+	enable, disable, raw := true, false, 0
+	w := WATCH{Enable: &enable, JSON: &enable, NMEA: &disable, Raw: &raw, PPS: &disable}
 
-		var reportPeek gopsdReport
-		if err := sonic.Unmarshal(lineBytes, &reportPeek); err != nil {
-			continue
+	s.filterMu.RLock()
+	defer s.filterMu.RUnlock()
+	if len(s.filters["PPS"]) > 0 || len(s.filters["TOFF"]) > 0 || len(s.filters[AllClasses]) > 0 {
+		w.PPS = &enable
+	}
+	return w
+}
+
+// WATCH policy used by Watch: JSON reports from every device
+func defaultWatch() WATCH {
+	enable := true
+	return WATCH{Enable: &enable, JSON: &enable}
+}
+
+// Build a WATCH command, restricted to the device named in the Dial address
+func (s *Session) watchCommand(w WATCH) ([]byte, error) {
+	w.Class = "WATCH"
+	if w.Device == nil && s.endpoint.device != "" {
+		w.Device = &s.endpoint.device
+	}
+
+	body, err := json.Marshal(&w)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte("?WATCH="), body...), ';'), nil
+}
+
+// Send a command to GPSD
+func (s *Session) SendCommand(command string) error {
+	return s.SendCommandContext(context.Background(), command)
+}
+
+// Send a command to GPSD, abandoning the write if ctx is cancelled
+func (s *Session) SendCommandContext(ctx context.Context, command string) error {
+	return s.write(ctx, []byte("?"+command+";"))
+}
+
+// Write raw bytes to the current connection honouring ctx
+func (s *Session) write(ctx context.Context, b []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Hold only writeMu while blocked so Close can unblock the write
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrClosed
+	}
+	conn := s.conn
+	s.mu.Unlock()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetWriteDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetWriteDeadline(time.Unix(1, 0)) })
+	defer func() {
+		stop()
+		_ = conn.SetWriteDeadline(time.Time{})
+	}()
+
+	if _, err := conn.Write(b); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
 		}
+		if s.isClosed() {
+			return ErrClosed
+		}
+		return newProtocolError(ErrWrite, b, err)
+	}
+	return nil
+}
+
+// Attach a filter to a class of reports, the handle removes it again
+func (s *Session) AddFilter(class string, f Filter) *Handle {
+	return s.addFilter(f, class)
+}
 
-		if report := s.unmarshalReport(reportPeek.Class, lineBytes); report != nil {
-			if filtersRaw, ok := s.filters.Load(reportPeek.Class); ok {
-				s.dispatchReport(report, filtersRaw.([]Filter))
+// Attach one filter to several classes behind a single handle
+func (s *Session) addFilter(f Filter, classes ...string) *Handle {
+	entry := &filterEntry{f: f}
+
+	s.filterMu.Lock()
+	defer s.filterMu.Unlock()
+	for _, class := range classes {
+		filters := s.filters[class]
+		s.filters[class] = append(filters[:len(filters):len(filters)], entry)
+	}
+
+	return &Handle{session: s, classes: classes, entry: entry}
+}
+
+// Detach the filter, safe to call while reports are being dispatched.
+// A report already being dispatched may still reach the filter once.
+func (h *Handle) Unsubscribe() {
+	s := h.session
+	s.filterMu.Lock()
+	defer s.filterMu.Unlock()
+
+	for _, class := range h.classes {
+		filters := s.filters[class]
+		for i, entry := range filters {
+			if entry != h.entry {
+				continue
+			}
+			remaining := make([]*filterEntry, 0, len(filters)-1)
+			remaining = append(append(remaining, filters[:i]...), filters[i+1:]...)
+			if len(remaining) == 0 {
+				delete(s.filters, class)
+			} else {
+				s.filters[class] = remaining
 			}
+			break
 		}
 	}
 }
 
-// Convert a report to a struct
-func (s *Session) unmarshalReport(class string, data []byte) interface{} {
-	var report interface{}
-	switch class {
-	case "TPV":
-		var r TPV
-		if err := sonic.Unmarshal(data, &r); err == nil {
-			report = &r
+// Snapshot of the filters attached to a class
+func (s *Session) filtersFor(class string) []*filterEntry {
+	s.filterMu.RLock()
+	defer s.filterMu.RUnlock()
+	return s.filters[class]
+}
+
+// Safely close the GPSD connection
+func (s *Session) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	s.closed = true
+	close(s.quit)
+	return s.conn.Close()
+}
+
+// Handle report watching, reconnecting and dispatching
+func (s *Session) watchReports(ctx context.Context, done chan<- bool) {
+	var err error
+	defer func() {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		} else if s.isClosed() {
+			err = ErrClosed
 		}
-	case "SKY":
-		var r SKY
-		if err := sonic.Unmarshal(data, &r); err == nil {
-			report = &r
+		s.mu.Lock()
+		s.err = err
+		s.mu.Unlock()
+		done <- true
+		close(done)
+	}()
+
+	for {
+		s.mu.Lock()
+		reader := s.reader
+		s.mu.Unlock()
+
+		s.setState(StateWatching)
+		err = s.readReports(reader)
+
+		if s.isClosed() || ctx.Err() != nil {
+			return
 		}
-	case "GST":
-		var r GST
-		if err := sonic.Unmarshal(data, &r); err == nil {
-			report = &r
+		s.reportError(err)
+		if s.backoff == nil {
+			return
 		}
-	case "ATT":
-		var r ATT
-		if err := sonic.Unmarshal(data, &r); err == nil {
-			report = &r
+		s.setState(StateLost)
+		if !s.reconnect(ctx) {
+			if !s.isClosed() {
+				s.setState(StateGivenUp)
+			}
+			return
 		}
-	case "DEVICES":
-		var r DEVICES
-		if err := sonic.Unmarshal(data, &r); err == nil {
-			report = &r
+	}
+}
+
+// Read and dispatch reports until the connection drops. Lines longer
+// than the maximum line size are skipped up to the next newline.
+func (s *Session) readReports(reader *bufio.Reader) error {
+	var line []byte
+	skipping := false
+
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if err != nil && !errors.Is(err, bufio.ErrBufferFull) {
+			if err == io.EOF {
+				return ErrConnectionLost
+			}
+			return fmt.Errorf("%w: %w", ErrConnectionLost, err)
 		}
-	case "PPS":
-		var r PPS
-		if err := sonic.Unmarshal(data, &r); err == nil {
-			report = &r
+
+		if !skipping {
+			if len(line)+len(chunk) > s.maxLine {
+				skipping = true
+				line = append(line, chunk...)[:min(len(line)+len(chunk), maxErrorLine)]
+			} else if err == nil && len(line) == 0 {
+				s.handleLine(bytes.TrimRight(chunk, "\r\n"))
+				continue
+			} else {
+				line = append(line, chunk...)
+			}
 		}
-	case "TOFF":
-		var r TOFF
-		if err := sonic.Unmarshal(data, &r); err == nil {
-			report = &r
+		if err != nil {
+			continue
 		}
-	case "ERROR":
-		var r ERROR
-		if err := sonic.Unmarshal(data, &r); err == nil {
-			report = &r
+
+		if skipping {
+			s.skipped.Add(1)
+			s.reportError(newProtocolError(ErrLineTooLong, line, nil))
+			skipping = false
+		} else {
+			s.handleLine(bytes.TrimRight(line, "\r\n"))
 		}
+		line = line[:0]
+	}
+}
+
+// Number of reports skipped for exceeding the maximum line size
+func (s *Session) SkippedLines() uint64 {
+	return s.skipped.Load()
+}
+
+// Skip reports longer than n bytes instead of the default 64 KiB
+func WithMaxLineSize(n int) Option {
+	return func(s *Session) { s.maxLine = n }
+}
+
+// Report whether Close has been called
+func (s *Session) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// Decode and dispatch one report. The class is scanned from the raw line
+// and reports nobody subscribed to are dropped without being decoded.
+func (s *Session) handleLine(line []byte) {
+	class, ok := peekClass(line)
+	if !ok {
+		s.reportError(newProtocolError(ErrMalformedJSON, line, errors.New("missing class")))
+		return
+	}
+
+	filters, all := s.filtersFor(class), s.filtersFor(AllClasses)
+	rt, known := reportTypes[class]
+	if !known && class != "AIS" {
+		s.dispatchUnknown(class, line)
+		return
+	}
+	if len(filters) == 0 && len(all) == 0 && s.cache == nil {
+		return
+	}
+
+	report, err := s.unmarshalReport(class, line)
+	if err != nil {
+		s.reportError(newProtocolError(ErrMalformedJSON, line, err))
+		return
+	}
+
+	if s.cache != nil {
+		s.cache.store(class, line, report)
+	}
+	s.dispatchReport(report, filters)
+	s.dispatchReport(report, all)
+	if known {
+		rt.put(report, s.pooled)
 	}
-	return report
+}
+
+// Convert a report to a struct, nil for classes without a struct
+func (s *Session) unmarshalReport(class string, data []byte) (Report, error) {
+	if class == "AIS" {
+		return unmarshalAIS(s.decoder, data)
+	}
+	rt, ok := reportTypes[class]
+	if !ok {
+		return nil, nil
+	}
+
+	report := rt.get(s.pooled)
+	if err := s.decoder.Unmarshal(data, report); err != nil {
+		rt.put(report, s.pooled)
+		return nil, err
+	}
+	if r, ok := report.(presenceRecorder); ok {
+		r.recordPresence(data)
+	}
+	return report, nil
+}
+
+// Recycle stream reports (TPV, SKY, GST, ATT, ...) after dispatch to cut
+// allocations on high-rate receivers. Filters added with AddFilter or On
+// must not retain reports past their return, Subscribe and SubscribeFunc
+// queue private copies instead and so allocate as before.
+func WithPooledReports() Option {
+	return func(s *Session) { s.pooled = true }
+}
+
+// Copy a report that will be recycled after dispatch, for filters keeping it
+func (s *Session) retain(r Report) Report {
+	if !s.pooled {
+		return r
+	}
+	if rt, ok := reportTypes[r.ReportClass()]; ok && rt.pooled {
+		return rt.clone(r)
+	}
+	return r
+}
+
+// Hand a report of an unmodelled class to the catch-all filters
+func (s *Session) dispatchUnknown(class string, data []byte) {
+	filters := s.filtersFor(UnknownClass)
+	if len(filters) == 0 {
+		s.reportError(newProtocolError(ErrUnknownClass, data, nil))
+	}
+
+	report := &RawReport{Class: class, Data: append([]byte(nil), data...)}
+	s.dispatchReport(report, filters)
+	s.dispatchReport(report, s.filtersFor(AllClasses))
 }
 
 // Call all filters for a class
-func (s *Session) dispatchReport(report interface{}, filters []Filter) {
-	for _, f := range filters {
-		f(report)
+func (s *Session) dispatchReport(report Report, filters []*filterEntry) {
+	for _, entry := range filters {
+		entry.f(report)
 	}
 }
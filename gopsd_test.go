@@ -0,0 +1,224 @@
+package gopsd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testBanner = `{"class":"VERSION","release":"3.25","rev":"3.25","proto_major":3,"proto_minor":15}`
+
+// Minimal gpsd sending the banner, then lines once a WATCH arrives
+type fakeGPSD struct {
+	ln       net.Listener
+	lines    []string
+	drop     bool                          // Hang up after sending lines
+	reply    func(command string) []string // Answer to commands, lines when nil
+	accepted atomic.Int64
+}
+
+func newFakeGPSD(t testing.TB, drop bool, lines ...string) *fakeGPSD {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeGPSD{ln: ln, lines: lines, drop: drop}
+	t.Cleanup(func() { _ = ln.Close() })
+	go f.serve()
+	return f
+}
+
+func (f *fakeGPSD) addr() string { return f.ln.Addr().String() }
+
+func (f *fakeGPSD) serve() {
+	for {
+		c, err := f.ln.Accept()
+		if err != nil {
+			return
+		}
+		f.accepted.Add(1)
+		go f.handle(c)
+	}
+}
+
+func (f *fakeGPSD) handle(c net.Conn) {
+	defer c.Close()
+	if _, err := c.Write([]byte(testBanner + "\n")); err != nil {
+		return
+	}
+	r := bufio.NewReader(c)
+	for {
+		command, err := r.ReadString(';')
+		if err != nil {
+			return
+		}
+		lines := f.lines
+		if f.reply != nil {
+			lines = f.reply(command)
+		}
+		for _, line := range lines {
+			if _, err := c.Write([]byte(line + "\n")); err != nil {
+				return
+			}
+		}
+		if f.drop {
+			return
+		}
+	}
+}
+
+// Open file descriptors of the test process
+func openFDs(t *testing.T) int {
+	t.Helper()
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Skip("no /proc/self/fd on this platform")
+	}
+	return len(entries)
+}
+
+func TestReconnectDoesNotLeakConnections(t *testing.T) {
+	const reconnects = 100
+
+	f := newFakeGPSD(t, true, `{"class":"TPV","device":"/dev/ttyACM0","mode":3,"lat":1,"lon":2}`)
+	s, err := Dial(f.addr(), WithReconnect(Backoff{Initial: time.Millisecond, Max: time.Millisecond}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	var fixes atomic.Int64
+	On(s, func(*TPV) { fixes.Add(1) })
+	s.Watch()
+
+	waitFor := func(n int64) {
+		deadline := time.Now().Add(10 * time.Second)
+		for fixes.Load() < n {
+			if time.Now().After(deadline) {
+				t.Fatalf("got %d fixes, want %d", fixes.Load(), n)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	waitFor(5)
+	before := openFDs(t)
+	waitFor(5 + reconnects)
+	after := openFDs(t)
+
+	if accepted := f.accepted.Load(); accepted < reconnects {
+		t.Fatalf("only %d connections accepted", accepted)
+	}
+	if after > before+5 {
+		t.Fatalf("open file descriptors grew from %d to %d over %d reconnects", before, after, reconnects)
+	}
+}
+
+func TestWatchAfterCloseReportsError(t *testing.T) {
+	f := newFakeGPSD(t, false)
+	var hooked error
+	s, err := Dial(f.addr(), WithErrorHook(func(err error) { hooked = err }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = s.Close()
+
+	select {
+	case <-s.Watch():
+	case <-time.After(time.Second):
+		t.Fatal("Watch channel not signalled")
+	}
+	if !errors.Is(s.Err(), ErrClosed) {
+		t.Fatalf("Err() = %v, want ErrClosed", s.Err())
+	}
+	if !errors.Is(hooked, ErrClosed) {
+		t.Fatalf("error hook got %v, want ErrClosed", hooked)
+	}
+}
+
+func TestWatchWithRejectedPolicyIsNotReplayed(t *testing.T) {
+	f := newFakeGPSD(t, false)
+	f.reply = func(command string) []string {
+		if strings.Contains(command, `"raw":2`) {
+			return []string{`{"class":"ERROR","message":"raw mode unsupported"}`}
+		}
+		return []string{`{"class":"WATCH","enable":true,"json":true}`}
+	}
+	s, err := Dial(f.addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	enable := true
+	if _, err := s.WatchWith(ctx, WATCH{Enable: &enable, JSON: &enable}); err != nil {
+		t.Fatal(err)
+	}
+	s.mu.Lock()
+	accepted := string(s.watch)
+	s.mu.Unlock()
+
+	raw := 2
+	var gpsdErr *ERROR
+	if _, err := s.WatchWith(ctx, WATCH{Enable: &enable, Raw: &raw}); !errors.As(err, &gpsdErr) {
+		t.Fatalf("WatchWith error = %v, want *ERROR", err)
+	}
+	s.mu.Lock()
+	replayed := string(s.watch)
+	s.mu.Unlock()
+	if replayed != accepted {
+		t.Fatalf("replayed WATCH %s, want the accepted %s", replayed, accepted)
+	}
+}
+
+func TestCloseUnblocksStalledWrite(t *testing.T) {
+	// A peer that sends the banner and then never reads
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		c, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		_, _ = c.Write([]byte(testBanner + "\n"))
+		<-stop
+	}()
+
+	s, err := Dial(ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	written := make(chan error, 1)
+	go func() { written <- s.SendCommand(strings.Repeat("x", 64<<20)) }()
+
+	time.Sleep(100 * time.Millisecond)
+	closed := make(chan error, 1)
+	go func() { closed <- s.Close() }()
+
+	var errs [2]error
+	for i, ch := range []chan error{closed, written} {
+		select {
+		case errs[i] = <-ch:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Close did not unblock a write to a stalled peer")
+		}
+	}
+	if !errors.Is(errs[1], ErrClosed) {
+		t.Fatalf("SendCommand error = %v, want ErrClosed", errs[1])
+	}
+}
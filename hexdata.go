@@ -0,0 +1,33 @@
+package gopsd
+
+import "encoding/binary"
+
+// Build a u-blox UBX frame with its Fletcher checksum
+func UBXFrame(class, id byte, payload []byte) []byte {
+	frame := make([]byte, 0, len(payload)+8)
+	frame = append(frame, 0xB5, 0x62, class, id)
+	frame = binary.LittleEndian.AppendUint16(frame, uint16(len(payload)))
+	frame = append(frame, payload...)
+
+	var ckA, ckB byte
+	for _, b := range frame[2:] {
+		ckA += b
+		ckB += ckA
+	}
+	return append(frame, ckA, ckB)
+}
+
+// Build a SiRF binary frame with its 15-bit checksum
+func SiRFFrame(payload []byte) []byte {
+	frame := make([]byte, 0, len(payload)+8)
+	frame = append(frame, 0xA0, 0xA2)
+	frame = binary.BigEndian.AppendUint16(frame, uint16(len(payload))&0x7FFF)
+	frame = append(frame, payload...)
+
+	var sum uint16
+	for _, b := range payload {
+		sum += uint16(b)
+	}
+	frame = binary.BigEndian.AppendUint16(frame, sum&0x7FFF)
+	return append(frame, 0xB0, 0xB3)
+}
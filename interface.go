@@ -9,8 +9,11 @@ package gopsd
 
 import (
 	"bufio"
+	"encoding/json"
 	"net"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Constants
@@ -21,79 +24,169 @@ const (
 	Mode2D  Mode = 2
 	Mode3D  Mode = 3
 
+	// fix status constants
+	StatusUnknown   FixStatus = 0 // Unknown
+	StatusGPS       FixStatus = 1 // Normal GNSS fix
+	StatusDGPS      FixStatus = 2 // Differential GNSS
+	StatusRTKFixed  FixStatus = 3 // RTK with fixed integers
+	StatusRTKFloat  FixStatus = 4 // RTK with floating integers
+	StatusDR        FixStatus = 5 // Dead reckoning only
+	StatusGNSSDR    FixStatus = 6 // GNSS combined with dead reckoning
+	StatusTime      FixStatus = 7 // Time only, surveyed-in fixed position
+	StatusSimulated FixStatus = 8 // Simulated
+	StatusPY        FixStatus = 9 // P(Y) code
+
+	// antenna status constants
+	AntennaUnknown AntennaStatus = 0
+	AntennaOK      AntennaStatus = 1
+	AntennaOpen    AntennaStatus = 2
+	AntennaShort   AntennaStatus = 3
+
+	// constellation constants
+	GNSSGPS     GNSS = 0
+	GNSSSBAS    GNSS = 1
+	GNSSGalileo GNSS = 2
+	GNSSBeiDou  GNSS = 3
+	GNSSIMES    GNSS = 4
+	GNSSQZSS    GNSS = 5
+	GNSSGLONASS GNSS = 6
+	GNSSNavIC   GNSS = 7
+
 	DefaultAddress = "localhost:2947"
+	AllClasses     = "*"       // Filter class matching every report
+	UnknownClass   = "UNKNOWN" // Filter class receiving a RawReport for unmodelled classes
 )
 
 // Interfaces
 
 type Filter func(interface{}) // GPSD Server Filter function
 
+// Report implemented by every decoded GPSD class
+type Report interface {
+	ReportClass() string // Class name as sent by GPSD
+}
+
 type Mode byte // Fix Mode (0: No Value, 1: No Fix, 2: 2D, 3: 3D)
 
+type FixStatus byte // GNSS fix status (DGPS, RTK fixed/float, dead reckoning, ...)
+
+type AntennaStatus byte // Antenna status (0: Unknown, 1: OK, 2: Open, 3: Short)
+
+type GNSS byte // GNSS constellation ID as used by u-blox
+
+// Time GPSD sends either as an ISO 8601 string or as seconds since the
+// epoch, where 0 stands for none, e.g. the activation time of a removed device
+type Timestamp struct {
+	time.Time
+}
+
+type Option func(*Session) // Session configuration applied by Dial
+
 type Session struct {
+	endpoint endpoint        // Address used to (re)connect to the GPSD Server
+	timeout  time.Duration   // Dial timeout, zero for none
+	backoff  *Backoff        // Reconnect policy, nil disables reconnecting
+	onState  func(ConnState) // Connection state change hook
+	onError  func(error)     // Read, decode and write error hook
+	minProto [2]int          // Minimum protocol major and minor version
+	decoder  Decoder         // JSON decoder for reports
+	pooled   bool            // Recycle stream reports after dispatch
+	maxLine  int             // Longest report accepted, in bytes
+	skipped  atomic.Uint64   // Reports skipped for exceeding maxLine
+	cache    *stateCache     // Latest report per class and device, nil if disabled
+
+	mu      sync.Mutex    // Guards connection and reader state
 	conn    net.Conn      // Client GPSD Server connection
 	reader  *bufio.Reader // Client GPSD Server reader
-	filters sync.Map      // Filters for the GPSD Server
+	version *VERSION      // Banner sent by the GPSD Server on connect
+	watch   []byte        // Last WATCH command sent, replayed on reconnect
+	closed  bool          // Set once Close has been called
+	quit    chan struct{} // Closed by Close to abort reconnecting
+	done    chan bool     // Signalled then closed when the reader stops
+	reading bool          // Set once the report reader has been spawned
+	err     error         // Reason the watcher stopped
+
+	writeMu  sync.Mutex                // Serialises writes and their deadlines
+	reqMu    sync.Mutex                // Serialises command requests
+	filterMu sync.RWMutex              // Guards filters
+	filters  map[string][]*filterEntry // Filters for the GPSD Server, replaced on change
 }
 
-type gopsdReport struct {
-	Class string `json:"class"` // Type of report
+type Handle struct {
+	session *Session     // Session the filter is attached to
+	classes []string     // Classes the filter is attached to
+	entry   *filterEntry // Attached filter
+}
+
+type filterEntry struct {
+	f Filter // Filter, boxed so handles can find it by identity
+}
+
+type VERSION struct {
+	Class      string  `json:"class"`            // Fixed: "VERSION"
+	Release    string  `json:"release"`          // Public release level
+	Rev        string  `json:"rev"`              // Internal revision-control level
+	ProtoMajor int     `json:"proto_major"`      // API major revision level
+	ProtoMinor int     `json:"proto_minor"`      // API minor revision level
+	Remote     *string `json:"remote,omitempty"` // URL of the remote daemon (optional)
 }
 
 type TPV struct {
-	Class       string  `json:"class"`                 // Fixed: "TPV"
-	Device      string  `json:"device,omitempty"`      // Name of the originating device.
-	Mode        int     `json:"mode"`                  // The mode of operation
-	Alt         float64 `json:"alt,omitempty"`         // Altitude (meters)
-	AltHAE      float64 `json:"altHAE,omitempty"`      // Altitude above ellipsoid (meters)
-	AltMSL      float64 `json:"altMSL,omitempty"`      // Altitude above mean sea level (meters)
-	Ant         int     `json:"ant,omitempty"`         // Antenna status
-	Climb       float64 `json:"climb,omitempty"`       // Climb rate (meters per second)
-	ClockBias   float64 `json:"clockbias,omitempty"`   // Clock bias (seconds)
-	ClockDrift  float64 `json:"clockdrift,omitempty"`  // Clock drift (seconds per second)
-	Datum       string  `json:"datum,omitempty"`       // Datum used for coordinates
-	Depth       float64 `json:"depth,omitempty"`       // Depth (meters)
-	DgpsAge     float64 `json:"dgpsAge,omitempty"`     // DGPS age (seconds)
-	DgpsSta     float64 `json:"dgpsSta,omitempty"`     // DGPS station ID
-	Ecefx       float64 `json:"ecefx,omitempty"`       // ECEF X coordinate (meters)
-	Ecefy       float64 `json:"ecefy,omitempty"`       // ECEF Y coordinate (meters)
-	Ecefz       float64 `json:"ecefz,omitempty"`       // ECEF Z coordinate (meters)
-	EcefpAcc    float64 `json:"ecefpAcc,omitempty"`    // ECEF position accuracy (meters)
-	Ecefvx      float64 `json:"ecefvx,omitempty"`      // ECEF velocity X component (meters per second)
-	Ecefvy      float64 `json:"ecefvy,omitempty"`      // ECEF velocity Y component (meters per second)
-	Ecefvz      float64 `json:"ecefvz,omitempty"`      // ECEF velocity Z component (meters per second)
-	EcefvAcc    float64 `json:"ecefvAcc,omitempty"`    // ECEF velocity accuracy (meters per second)
-	Epc         float64 `json:"epc,omitempty"`         // Ephemeris clock bias (seconds)
-	Epd         float64 `json:"epd,omitempty"`         // Ephemeris clock drift (seconds per second)
-	Eph         float64 `json:"eph,omitempty"`         // Ephemeris health status
-	Eps         float64 `json:"eps,omitempty"`         // Ephemeris signal strength (dB)
-	Ept         float64 `json:"ept"`                   // Ephemeris time (seconds)
-	Epx         float64 `json:"epx,omitempty"`         // Ephemeris X position (meters)
-	Epy         float64 `json:"epy,omitempty"`         // Ephemeris Y position (meters)
-	Epv         float64 `json:"epv,omitempty"`         // Ephemeris Z position (meters)
-	GeoidSep    float64 `json:"geoidSep,omitempty"`    // Geoid separation (meters)
-	Jam         int     `json:"jam,omitempty"`         // Jam status
-	Lat         float64 `json:"lat,omitempty"`         // Latitude (degrees)
-	LeapSeconds int     `json:"leapseconds,omitempty"` // Leap seconds correction
-	Lon         float64 `json:"lon,omitempty"`         // Longitude (degrees)
-	MagTrack    float64 `json:"magtrack,omitempty"`    // Magnetic track (degrees)
-	MagVar      float64 `json:"magvar,omitempty"`      // Magnetic variation (degrees)
-	RelD        float64 `json:"relD,omitempty"`        // Relative distance (meters)
-	RelE        float64 `json:"relE,omitempty"`        // Relative east (meters)
-	RelN        float64 `json:"relN,omitempty"`        // Relative north (meters)
-	Sep         float64 `json:"sep,omitempty"`         // Separation distance (meters)
-	Speed       float64 `json:"speed,omitempty"`       // Speed (meters per second)
-	Status      int     `json:"status,omitempty"`      // Status of the GPS fix
-	Temp        float64 `json:"temp,omitempty"`        // Temperature (degrees Celsius)
-	Time        string  `json:"time,omitempty"`        // Timestamp of the fix
-	Track       float64 `json:"track,omitempty"`       // Heading (degrees)
-	VelD        float64 `json:"velD,omitempty"`        // Vertical velocity (meters per second)
-	VelE        float64 `json:"velE,omitempty"`        // East velocity (meters per second)
-	VelN        float64 `json:"velN,omitempty"`        // North velocity (meters per second)
-	Wanglem     float64 `json:"wanglem,omitempty"`     // Longitude of the antenna (degrees)
-	Wangler     float64 `json:"wangler,omitempty"`     // Latitude of the antenna (degrees)
-	Wanglet     float64 `json:"wanglet,omitempty"`     // Altitude of the antenna (meters)
-	Wspeedr     float64 `json:"wspeedr,omitempty"`     // Relative wind speed (meters per second)
+	Class       string        `json:"class"`                 // Fixed: "TPV"
+	Device      string        `json:"device,omitempty"`      // Name of the originating device.
+	Mode        Mode          `json:"mode"`                  // The mode of operation
+	Alt         float64       `json:"alt,omitempty"`         // Altitude (meters)
+	AltHAE      float64       `json:"altHAE,omitempty"`      // Altitude above ellipsoid (meters)
+	AltMSL      float64       `json:"altMSL,omitempty"`      // Altitude above mean sea level (meters)
+	Ant         AntennaStatus `json:"ant,omitempty"`         // Antenna status
+	Climb       float64       `json:"climb,omitempty"`       // Climb rate (meters per second)
+	ClockBias   float64       `json:"clockbias,omitempty"`   // Clock bias (seconds)
+	ClockDrift  float64       `json:"clockdrift,omitempty"`  // Clock drift (seconds per second)
+	Datum       string        `json:"datum,omitempty"`       // Datum used for coordinates
+	Depth       float64       `json:"depth,omitempty"`       // Depth (meters)
+	DgpsAge     float64       `json:"dgpsAge,omitempty"`     // DGPS age (seconds)
+	DgpsSta     float64       `json:"dgpsSta,omitempty"`     // DGPS station ID
+	Ecefx       float64       `json:"ecefx,omitempty"`       // ECEF X coordinate (meters)
+	Ecefy       float64       `json:"ecefy,omitempty"`       // ECEF Y coordinate (meters)
+	Ecefz       float64       `json:"ecefz,omitempty"`       // ECEF Z coordinate (meters)
+	EcefpAcc    float64       `json:"ecefpAcc,omitempty"`    // ECEF position accuracy (meters)
+	Ecefvx      float64       `json:"ecefvx,omitempty"`      // ECEF velocity X component (meters per second)
+	Ecefvy      float64       `json:"ecefvy,omitempty"`      // ECEF velocity Y component (meters per second)
+	Ecefvz      float64       `json:"ecefvz,omitempty"`      // ECEF velocity Z component (meters per second)
+	EcefvAcc    float64       `json:"ecefvAcc,omitempty"`    // ECEF velocity accuracy (meters per second)
+	Epc         float64       `json:"epc,omitempty"`         // Ephemeris clock bias (seconds)
+	Epd         float64       `json:"epd,omitempty"`         // Ephemeris clock drift (seconds per second)
+	Eph         float64       `json:"eph,omitempty"`         // Ephemeris health status
+	Eps         float64       `json:"eps,omitempty"`         // Ephemeris signal strength (dB)
+	Ept         float64       `json:"ept"`                   // Ephemeris time (seconds)
+	Epx         float64       `json:"epx,omitempty"`         // Ephemeris X position (meters)
+	Epy         float64       `json:"epy,omitempty"`         // Ephemeris Y position (meters)
+	Epv         float64       `json:"epv,omitempty"`         // Ephemeris Z position (meters)
+	GeoidSep    float64       `json:"geoidSep,omitempty"`    // Geoid separation (meters)
+	Jam         int           `json:"jam,omitempty"`         // Jam status
+	Lat         float64       `json:"lat,omitempty"`         // Latitude (degrees)
+	LeapSeconds int           `json:"leapseconds,omitempty"` // Leap seconds correction
+	Lon         float64       `json:"lon,omitempty"`         // Longitude (degrees)
+	MagTrack    float64       `json:"magtrack,omitempty"`    // Magnetic track (degrees)
+	MagVar      float64       `json:"magvar,omitempty"`      // Magnetic variation (degrees)
+	RelD        float64       `json:"relD,omitempty"`        // Relative distance (meters)
+	RelE        float64       `json:"relE,omitempty"`        // Relative east (meters)
+	RelN        float64       `json:"relN,omitempty"`        // Relative north (meters)
+	Sep         float64       `json:"sep,omitempty"`         // Separation distance (meters)
+	Speed       float64       `json:"speed,omitempty"`       // Speed (meters per second)
+	Status      FixStatus     `json:"status,omitempty"`      // Status of the GPS fix
+	Temp        float64       `json:"temp,omitempty"`        // Temperature (degrees Celsius)
+	Time        time.Time     `json:"time,omitempty"`        // Timestamp of the fix
+	Track       float64       `json:"track,omitempty"`       // Heading (degrees)
+	VelD        float64       `json:"velD,omitempty"`        // Vertical velocity (meters per second)
+	VelE        float64       `json:"velE,omitempty"`        // East velocity (meters per second)
+	VelN        float64       `json:"velN,omitempty"`        // North velocity (meters per second)
+	Wanglem     float64       `json:"wanglem,omitempty"`     // Longitude of the antenna (degrees)
+	Wangler     float64       `json:"wangler,omitempty"`     // Latitude of the antenna (degrees)
+	Wanglet     float64       `json:"wanglet,omitempty"`     // Altitude of the antenna (meters)
+	Wspeedr     float64       `json:"wspeedr,omitempty"`     // Relative wind speed (meters per second)
+
+	present fieldSet // Members sent by GPSD, see Has
 }
 
 type Satellite struct {
@@ -101,7 +194,7 @@ type Satellite struct {
 	Az     float64 `json:"az"`               // Azimuth, degrees from true north
 	El     float64 `json:"el"`               // Elevation in degrees
 	FreqID int     `json:"freqid,omitempty"` // For GLONASS: the frequency ID of the signal
-	GNSSID int     `json:"gnssid,omitempty"` // The GNSS ID
+	GNSSID GNSS    `json:"gnssid,omitempty"` // The GNSS ID
 	Health int     `json:"health,omitempty"` // Health of the satellite (0=unknown, 1=OK, 2=unhealthy)
 	SS     float64 `json:"ss,omitempty"`     // Signal to Noise ratio in dBHz
 	SigID  int     `json:"sigid,omitempty"`  // Signal ID of this signal
@@ -122,60 +215,68 @@ type SKY struct {
 	Qual       int         `json:"qual,omitempty"`       // Quality Indicator
 	Satellites []Satellite `json:"satellites,omitempty"` // List of satellite objects
 	Tdop       float64     `json:"tdop,omitempty"`       // Time dilution of precision
-	Time       string      `json:"time,omitempty"`       // Time/date stamp in ISO8601 format
+	Time       time.Time   `json:"time,omitempty"`       // Time/date stamp, UTC
 	USat       int         `json:"uSat,omitempty"`       // Number of satellites used in navigation
 	VDop       float64     `json:"vdop,omitempty"`       // Vertical dilution of precision
 	XDop       float64     `json:"xdop,omitempty"`       // Longitudinal dilution of precision
 	YDop       float64     `json:"ydop,omitempty"`       // Latitudinal dilution of precision
+
+	present fieldSet // Members sent by GPSD, see Has
 }
 
 type GST struct {
-	Class  string  `json:"class"`            // Fixed: "GST"
-	Device string  `json:"device,omitempty"` // Name of originating device
-	Time   string  `json:"time,omitempty"`   // Time/date stamp in ISO8601 format, UTC
-	RMS    float64 `json:"rms,omitempty"`    // Standard deviation of range inputs to the navigation process
-	Major  float64 `json:"major,omitempty"`  // Standard deviation of semi-major axis of error ellipse (meters)
-	Minor  float64 `json:"minor,omitempty"`  // Standard deviation of semi-minor axis of error ellipse (meters)
-	Orient float64 `json:"orient,omitempty"` // Orientation of semi-major axis of error ellipse (degrees from true north)
-	Alt    float64 `json:"alt,omitempty"`    // Standard deviation of altitude error (meters)
-	Lat    float64 `json:"lat,omitempty"`    // Standard deviation of latitude error (meters)
-	Lon    float64 `json:"lon,omitempty"`    // Standard deviation of longitude error (meters)
-	VE     float64 `json:"ve,omitempty"`     // Standard deviation of East velocity error (meters/second)
-	VN     float64 `json:"vn,omitempty"`     // Standard deviation of North velocity error (meters/second)
-	VU     float64 `json:"vu,omitempty"`     // Standard deviation of Up velocity error (meters/second)
+	Class  string    `json:"class"`            // Fixed: "GST"
+	Device string    `json:"device,omitempty"` // Name of originating device
+	Time   time.Time `json:"time,omitempty"`   // Time/date stamp, UTC
+	RMS    float64   `json:"rms,omitempty"`    // Standard deviation of range inputs to the navigation process
+	Major  float64   `json:"major,omitempty"`  // Standard deviation of semi-major axis of error ellipse (meters)
+	Minor  float64   `json:"minor,omitempty"`  // Standard deviation of semi-minor axis of error ellipse (meters)
+	Orient float64   `json:"orient,omitempty"` // Orientation of semi-major axis of error ellipse (degrees from true north)
+	Alt    float64   `json:"alt,omitempty"`    // Standard deviation of altitude error (meters)
+	Lat    float64   `json:"lat,omitempty"`    // Standard deviation of latitude error (meters)
+	Lon    float64   `json:"lon,omitempty"`    // Standard deviation of longitude error (meters)
+	VE     float64   `json:"ve,omitempty"`     // Standard deviation of East velocity error (meters/second)
+	VN     float64   `json:"vn,omitempty"`     // Standard deviation of North velocity error (meters/second)
+	VU     float64   `json:"vu,omitempty"`     // Standard deviation of Up velocity error (meters/second)
+
+	present fieldSet // Members sent by GPSD, see Has
 }
 
 type ATT struct {
-	Class    string  `json:"class"`              // Fixed: "ATT"
-	Device   string  `json:"device"`             // Name of originating device
-	Time     string  `json:"time,omitempty"`     // Time/date stamp in ISO8601 format, UTC
-	TimeTag  string  `json:"timeTag,omitempty"`  // Arbitrary time tag of measurement
-	Heading  float64 `json:"heading,omitempty"`  // Heading, degrees from true north
-	MagSt    string  `json:"mag_st,omitempty"`   // Magnetometer status
-	MHeading float64 `json:"mheading,omitempty"` // Heading, degrees from magnetic north
-	Pitch    float64 `json:"pitch,omitempty"`    // Pitch in degrees
-	PitchSt  string  `json:"pitch_st,omitempty"` // Pitch sensor status
-	Rot      float64 `json:"rot,omitempty"`      // Rate of Turn in degrees per minute
-	Yaw      float64 `json:"yaw,omitempty"`      // Yaw in degrees
-	YawSt    string  `json:"yaw_st,omitempty"`   // Yaw sensor status
-	Roll     float64 `json:"roll,omitempty"`     // Roll in degrees
-	RollSt   string  `json:"roll_st,omitempty"`  // Roll sensor status
-	Dip      float64 `json:"dip,omitempty"`      // Local magnetic inclination, degrees
-	MagLen   float64 `json:"mag_len,omitempty"`  // Scalar magnetic field strength
-	MagX     float64 `json:"mag_x,omitempty"`    // X component of magnetic field strength
-	MagY     float64 `json:"mag_y,omitempty"`    // Y component of magnetic field strength
-	MagZ     float64 `json:"mag_z,omitempty"`    // Z component of magnetic field strength
-	AccLen   float64 `json:"acc_len,omitempty"`  // Scalar acceleration
-	AccX     float64 `json:"acc_x,omitempty"`    // X component of acceleration (m/s^2)
-	AccY     float64 `json:"acc_y,omitempty"`    // Y component of acceleration (m/s^2)
-	AccZ     float64 `json:"acc_z,omitempty"`    // Z component of acceleration (m/s^2)
-	GyroX    float64 `json:"gyro_x,omitempty"`   // X component of angular rate (deg/s)
-	GyroY    float64 `json:"gyro_y,omitempty"`   // Y component of angular rate (deg/s)
-	GyroZ    float64 `json:"gyro_z,omitempty"`   // Z component of angular rate (deg/s)
-	Depth    float64 `json:"depth,omitempty"`    // Water depth in meters
-	Temp     float64 `json:"temp,omitempty"`     // Temperature at the sensor (°C)
+	Class    string    `json:"class"`              // Fixed: "ATT"
+	Device   string    `json:"device"`             // Name of originating device
+	Time     time.Time `json:"time,omitempty"`     // Time/date stamp, UTC
+	TimeTag  string    `json:"timeTag,omitempty"`  // Arbitrary time tag of measurement
+	Heading  float64   `json:"heading,omitempty"`  // Heading, degrees from true north
+	MagSt    string    `json:"mag_st,omitempty"`   // Magnetometer status
+	MHeading float64   `json:"mheading,omitempty"` // Heading, degrees from magnetic north
+	Pitch    float64   `json:"pitch,omitempty"`    // Pitch in degrees
+	PitchSt  string    `json:"pitch_st,omitempty"` // Pitch sensor status
+	Rot      float64   `json:"rot,omitempty"`      // Rate of Turn in degrees per minute
+	Yaw      float64   `json:"yaw,omitempty"`      // Yaw in degrees
+	YawSt    string    `json:"yaw_st,omitempty"`   // Yaw sensor status
+	Roll     float64   `json:"roll,omitempty"`     // Roll in degrees
+	RollSt   string    `json:"roll_st,omitempty"`  // Roll sensor status
+	Dip      float64   `json:"dip,omitempty"`      // Local magnetic inclination, degrees
+	MagLen   float64   `json:"mag_len,omitempty"`  // Scalar magnetic field strength
+	MagX     float64   `json:"mag_x,omitempty"`    // X component of magnetic field strength
+	MagY     float64   `json:"mag_y,omitempty"`    // Y component of magnetic field strength
+	MagZ     float64   `json:"mag_z,omitempty"`    // Z component of magnetic field strength
+	AccLen   float64   `json:"acc_len,omitempty"`  // Scalar acceleration
+	AccX     float64   `json:"acc_x,omitempty"`    // X component of acceleration (m/s^2)
+	AccY     float64   `json:"acc_y,omitempty"`    // Y component of acceleration (m/s^2)
+	AccZ     float64   `json:"acc_z,omitempty"`    // Z component of acceleration (m/s^2)
+	GyroX    float64   `json:"gyro_x,omitempty"`   // X component of angular rate (deg/s)
+	GyroY    float64   `json:"gyro_y,omitempty"`   // Y component of angular rate (deg/s)
+	GyroZ    float64   `json:"gyro_z,omitempty"`   // Z component of angular rate (deg/s)
+	Depth    float64   `json:"depth,omitempty"`    // Water depth in meters
+	Temp     float64   `json:"temp,omitempty"`     // Temperature at the sensor (°C)
+
+	present fieldSet // Members sent by GPSD, see Has
 }
 
+type IMU ATT // Inertial measurement, same layout as ATT with Class "IMU"
+
 type TOFF struct {
 	Class     string  `json:"class"`      // Fixed: "TOFF"
 	Device    string  `json:"device"`     // Name of the originating device
@@ -206,6 +307,82 @@ type OSC struct {
 	Delta       float64 `json:"delta"`       // Time difference (in nanoseconds) between PPS output and the most recent GPS PPS input
 }
 
+type RawMeasurement struct {
+	GNSSID       GNSS    `json:"gnssid"`                 // The GNSS ID
+	SVID         int     `json:"svid"`                   // Satellite ID within its constellation
+	SigID        int     `json:"sigid,omitempty"`        // Signal ID of this signal
+	FreqID       int     `json:"freqid,omitempty"`       // For GLONASS: the frequency ID of the signal
+	ObsCode      string  `json:"obs,omitempty"`          // RINEX 3 observation code
+	SNR          float64 `json:"snr,omitempty"`          // Signal to noise ratio (dB-Hz)
+	LLI          int     `json:"lli,omitempty"`          // Loss of lock indicator
+	LockTime     float64 `json:"locktime,omitempty"`     // Carrier phase lock time (milliseconds)
+	CarrierPhase float64 `json:"carrierphase,omitempty"` // Carrier phase (cycles)
+	Pseudorange  float64 `json:"pseudorange,omitempty"`  // Pseudorange (meters)
+	Doppler      float64 `json:"doppler,omitempty"`      // Doppler (Hz)
+	C2C          float64 `json:"c2c,omitempty"`          // L1 C/A pseudorange (meters)
+	L2C          float64 `json:"l2c,omitempty"`          // L2 C pseudorange (meters)
+}
+
+type RAW struct {
+	Class   string           `json:"class"`   // Fixed: "RAW"
+	Device  string           `json:"device"`  // Name of the originating device
+	Time    int64            `json:"time"`    // Seconds since the Unix epoch of the measurements
+	NSec    int64            `json:"nsec"`    // Nanoseconds part of the measurement time
+	RawData []RawMeasurement `json:"rawdata"` // Measurements, one per signal
+}
+
+type SUBFRAME struct {
+	Class   string          `json:"class"`             // Fixed: "SUBFRAME"
+	Device  string          `json:"device"`            // Name of the originating device
+	TSV     int             `json:"tSV"`               // Transmitting satellite ID
+	TOW17   int             `json:"TOW17"`             // Truncated time of week (6 second units)
+	Frame   int             `json:"frame"`             // Subframe number
+	Scaled  bool            `json:"scaled"`            // True if values have been scaled
+	Ephem1  json.RawMessage `json:"EPHEM1,omitempty"`  // Subframe 1 clock data
+	Ephem2  json.RawMessage `json:"EPHEM2,omitempty"`  // Subframe 2 ephemeris data
+	Ephem3  json.RawMessage `json:"EPHEM3,omitempty"`  // Subframe 3 ephemeris data
+	Almanac json.RawMessage `json:"ALMANAC,omitempty"` // Almanac page
+	Iono    json.RawMessage `json:"IONO,omitempty"`    // Ionospheric and UTC data
+	Health  json.RawMessage `json:"HEALTH,omitempty"`  // Satellite health, SV 1-24
+	Health2 json.RawMessage `json:"HEALTH2,omitempty"` // Satellite health, SV 25-32
+	ERD     json.RawMessage `json:"ERD,omitempty"`     // Estimated range deviations
+}
+
+type RTCM2Satellite struct {
+	Ident int     `json:"ident"` // Satellite ID
+	UDRE  int     `json:"udre"`  // User differential range error
+	IOD   int     `json:"iod"`   // Issue of data
+	PRC   float64 `json:"prc"`   // Pseudorange correction (meters)
+	RRC   float64 `json:"rrc"`   // Range rate correction (meters per second)
+}
+
+type RTCM2 struct {
+	Class         string           `json:"class"`                // Fixed: "RTCM2"
+	Device        string           `json:"device"`               // Name of the originating device
+	Type          int              `json:"type"`                 // Message type
+	StationID     int              `json:"station_id"`           // Reference station ID
+	ZCount        float64          `json:"zcount"`               // Reference time (seconds into the hour)
+	SeqNum        int              `json:"seqnum"`               // Sequence number
+	Length        int              `json:"length"`               // Number of data words
+	StationHealth int              `json:"station_health"`       // Reference station health
+	Satellites    []RTCM2Satellite `json:"satellites,omitempty"` // Corrections (types 1 and 9)
+	X             float64          `json:"x,omitempty"`          // Reference station ECEF X (type 3, meters)
+	Y             float64          `json:"y,omitempty"`          // Reference station ECEF Y (type 3, meters)
+	Z             float64          `json:"z,omitempty"`          // Reference station ECEF Z (type 3, meters)
+	Message       string           `json:"message,omitempty"`    // Text message (type 16)
+	Data          []string         `json:"data,omitempty"`       // Undecoded data words in hex
+}
+
+type RTCM3 struct {
+	Class      string          `json:"class"`                // Fixed: "RTCM3"
+	Device     string          `json:"device"`               // Name of the originating device
+	Type       int             `json:"type"`                 // Message type
+	Length     int             `json:"length"`               // Payload length in bytes
+	StationID  int             `json:"station_id,omitempty"` // Reference station ID
+	Satellites json.RawMessage `json:"satellites,omitempty"` // Per satellite data, layout depends on type
+	Data       string          `json:"data,omitempty"`       // Undecoded payload in hex
+}
+
 type DEVICES struct {
 	Class   string   `json:"class"`            // Fixed: "DEVICES"
 	Devices []DEVICE `json:"devices"`          // List of device descriptions
@@ -213,22 +390,22 @@ type DEVICES struct {
 }
 
 type DEVICE struct {
-	Class     string  `json:"class"`               // Fixed: "DEVICE"
-	Activated string  `json:"activated,omitempty"` // Time the device was activated, if inactive this field is absent
-	Bps       int     `json:"bps,omitempty"`       // Device speed in bits per second
-	Cycle     float64 `json:"cycle,omitempty"`     // Device cycle time in seconds
-	Driver    string  `json:"driver,omitempty"`    // GPSD’s name for the device driver type
-	Flags     int     `json:"flags,omitempty"`     // Bit vector of property flags
-	Hexdata   string  `json:"hexdata,omitempty"`   // Data to send to the GNSS receiver in hexadecimal
-	Mincycle  float64 `json:"mincycle,omitempty"`  // Minimum cycle time in seconds (read-only)
-	Native    int     `json:"native,omitempty"`    // NMEA mode (0) or alternate mode (1)
-	Parity    string  `json:"parity,omitempty"`    // Parity: N, O or E (No parity, Odd, Even)
-	Path      string  `json:"path,omitempty"`      // Device path
-	Readonly  bool    `json:"readonly,omitempty"`  // True if device is read-only
-	Sernum    string  `json:"sernum,omitempty"`    // Hardware serial number
-	Stopbits  int     `json:"stopbits"`            // Stop bits (1 or 2)
-	Subtype   string  `json:"subtype,omitempty"`   // Version information
-	Subtype1  string  `json:"subtype1,omitempty"`  // Additional version information
+	Class     string    `json:"class"`               // Fixed: "DEVICE"
+	Activated Timestamp `json:"activated,omitempty"` // Time the device was activated, zero once deactivated
+	Bps       int       `json:"bps,omitempty"`       // Device speed in bits per second
+	Cycle     float64   `json:"cycle,omitempty"`     // Device cycle time in seconds
+	Driver    string    `json:"driver,omitempty"`    // GPSD’s name for the device driver type
+	Flags     int       `json:"flags,omitempty"`     // Bit vector of property flags
+	Hexdata   string    `json:"hexdata,omitempty"`   // Data to send to the GNSS receiver in hexadecimal
+	Mincycle  float64   `json:"mincycle,omitempty"`  // Minimum cycle time in seconds (read-only)
+	Native    int       `json:"native,omitempty"`    // NMEA mode (0) or alternate mode (1)
+	Parity    string    `json:"parity,omitempty"`    // Parity: N, O or E (No parity, Odd, Even)
+	Path      string    `json:"path,omitempty"`      // Device path
+	Readonly  bool      `json:"readonly,omitempty"`  // True if device is read-only
+	Sernum    string    `json:"sernum,omitempty"`    // Hardware serial number
+	Stopbits  int       `json:"stopbits"`            // Stop bits (1 or 2)
+	Subtype   string    `json:"subtype,omitempty"`   // Version information
+	Subtype1  string    `json:"subtype1,omitempty"`  // Additional version information
 }
 
 type WATCH struct {
@@ -246,14 +423,19 @@ type WATCH struct {
 }
 
 type POLL struct {
-	Class  string `json:"class"`  // Fixed: "POLL"
-	Time   string `json:"time"`   // Timestamp in ISO 8601 format
-	Active int    `json:"active"` // Count of active devices
-	TPV    []TPV  `json:"tpv"`    // List of TPV objects
-	Sky    []SKY  `json:"sky"`    // List of SKY objects
+	Class  string    `json:"class"`  // Fixed: "POLL"
+	Time   time.Time `json:"time"`   // Timestamp, UTC
+	Active int       `json:"active"` // Count of active devices
+	TPV    []TPV     `json:"tpv"`    // List of TPV objects
+	Sky    []SKY     `json:"sky"`    // List of SKY objects
 }
 
 type ERROR struct {
 	Class   string `json:"class"`   // Fixed: "ERROR"
 	Message string `json:"message"` // Textual error message
 }
+
+type RawReport struct {
+	Class string // Class named in the report
+	Data  []byte // The report as received
+}
@@ -0,0 +1,56 @@
+package gopsd
+
+import (
+	"math"
+
+	"github.com/AryaanSheth/gopsd/geo"
+)
+
+// Latitude and longitude of the fix
+func (r *TPV) Point() geo.Point {
+	return geo.Point{Lat: r.Lat, Lon: r.Lon}
+}
+
+// Distance in meters to another fix on the WGS84 ellipsoid
+func (r *TPV) DistanceTo(o *TPV) float64 {
+	return geo.Distance(r.Point(), o.Point())
+}
+
+// Initial great circle bearing towards another fix, degrees from true north
+func (r *TPV) BearingTo(o *TPV) float64 {
+	return geo.InitialBearing(r.Point(), o.Point())
+}
+
+// ECEF position of the fix, as reported by GPSD when present and
+// otherwise converted from Lat, Lon and AltHAE
+func (r *TPV) ECEF() geo.ECEF {
+	if r.Has("ecefx") && r.Has("ecefy") && r.Has("ecefz") {
+		return geo.ECEF{X: r.Ecefx, Y: r.Ecefy, Z: r.Ecefz}
+	}
+	return geo.ToECEF(r.Point(), r.AltHAE)
+}
+
+// Position of the fix in the local east, north, up frame of a reference fix
+func (r *TPV) ENU(ref *TPV) geo.ENU {
+	return geo.ToENU(r.ECEF(), ref.Point(), ref.AltHAE)
+}
+
+// Velocity in the local east, north, up frame, rotated from the ECEF
+// velocity when reported and otherwise derived from Speed, Track and Climb
+func (r *TPV) VelocityENU() geo.ENU {
+	if r.Has("ecefvx") && r.Has("ecefvy") && r.Has("ecefvz") {
+		return geo.RotateENU(geo.ECEF{X: r.Ecefvx, Y: r.Ecefvy, Z: r.Ecefvz}, r.Point())
+	}
+	sin, cos := math.Sincos(r.Track * math.Pi / 180)
+	return geo.ENU{E: r.Speed * sin, N: r.Speed * cos, U: r.Climb}
+}
+
+// UTM coordinate of the fix in its standard zone
+func (r *TPV) UTM() (geo.UTM, error) {
+	return geo.ToUTM(r.Point())
+}
+
+// MGRS reference of the fix with precision digits per axis (0-5)
+func (r *TPV) MGRS(precision int) (string, error) {
+	return geo.MGRS(r.Point(), precision)
+}
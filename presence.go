@@ -0,0 +1,100 @@
+package gopsd
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Members present in a decoded report, one bit per JSON member of the struct
+type fieldSet uint64
+
+// Bit assigned to each JSON member name of a report struct
+type fieldIndex map[string]fieldSet
+
+var (
+	tpvFields = indexFields[TPV]()
+	skyFields = indexFields[SKY]()
+	gstFields = indexFields[GST]()
+	attFields = indexFields[ATT]()
+)
+
+// Reports that record which members GPSD actually sent
+type presenceRecorder interface {
+	recordPresence(obj []byte)
+}
+
+// Assign a bit to every tagged field of T in declaration order
+func indexFields[T any]() fieldIndex {
+	t := reflect.TypeFor[T]()
+	index := fieldIndex{}
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		if len(index) == 64 {
+			panic("gopsd: " + t.Name() + " has more than 64 JSON members")
+		}
+		index[name] = 1 << len(index)
+	}
+	return index
+}
+
+// Set of the known top level members of obj
+func (index fieldIndex) scan(obj []byte) fieldSet {
+	var set fieldSet
+	eachMember(obj, func(key, _ []byte) bool {
+		set |= index[string(key)]
+		return true
+	})
+	return set
+}
+
+// Report whether GPSD sent the named JSON member, e.g. "lat" or "altHAE".
+// Tells a fix on the equator or at sea level apart from a missing value.
+// Always false for reports not decoded by a Session.
+func (r *TPV) Has(member string) bool { return r.present&tpvFields[member] != 0 }
+
+// Report whether GPSD sent the named JSON member, e.g. "hdop"
+func (r *SKY) Has(member string) bool { return r.present&skyFields[member] != 0 }
+
+// Report whether GPSD sent the named JSON member, e.g. "orient"
+func (r *GST) Has(member string) bool { return r.present&gstFields[member] != 0 }
+
+// Report whether GPSD sent the named JSON member, e.g. "pitch"
+func (r *ATT) Has(member string) bool { return r.present&attFields[member] != 0 }
+
+// Report whether GPSD sent the named JSON member, e.g. "acc_x"
+func (r *IMU) Has(member string) bool { return r.present&attFields[member] != 0 }
+
+func (r *TPV) recordPresence(obj []byte) { r.present = tpvFields.scan(obj) }
+func (r *SKY) recordPresence(obj []byte) { r.present = skyFields.scan(obj) }
+func (r *GST) recordPresence(obj []byte) { r.present = gstFields.scan(obj) }
+func (r *ATT) recordPresence(obj []byte) { r.present = attFields.scan(obj) }
+func (r *IMU) recordPresence(obj []byte) { r.present = attFields.scan(obj) }
+
+// Record presence for the TPV and SKY reports nested in a POLL response
+func (r *POLL) recordPresence(obj []byte) {
+	eachMember(obj, func(key, value []byte) bool {
+		i := 0
+		switch string(key) {
+		case "tpv":
+			eachElement(value, func(elem []byte) bool {
+				if i < len(r.TPV) {
+					r.TPV[i].recordPresence(elem)
+				}
+				i++
+				return true
+			})
+		case "sky":
+			eachElement(value, func(elem []byte) bool {
+				if i < len(r.Sky) {
+					r.Sky[i].recordPresence(elem)
+				}
+				i++
+				return true
+			})
+		}
+		return true
+	})
+}
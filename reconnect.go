@@ -0,0 +1,126 @@
+package gopsd
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// Connection state constants
+const (
+	StateConnecting ConnState = iota // Dialing the GPSD Server
+	StateWatching                    // Connected and reading reports
+	StateLost                        // Connection dropped, reconnect pending
+	StateGivenUp                     // Reconnect attempts exhausted
+)
+
+type ConnState byte // Connection state reported to the state hook
+
+// Exponential backoff policy used when redialing GPSD
+type Backoff struct {
+	Initial    time.Duration // Delay before the first redial attempt
+	Max        time.Duration // Upper bound on the delay between attempts
+	Multiplier float64       // Growth factor applied after each failed attempt
+	Jitter     float64       // Fraction (0-1) of the delay randomly added or removed
+	MaxRetries int           // Attempts before giving up, zero retries forever
+}
+
+// Reconnect policy suited to a local gpsd restarting after a hotplug
+var DefaultBackoff = Backoff{
+	Initial:    500 * time.Millisecond,
+	Max:        30 * time.Second,
+	Multiplier: 2,
+	Jitter:     0.2,
+}
+
+func (c ConnState) String() string {
+	switch c {
+	case StateConnecting:
+		return "connecting"
+	case StateWatching:
+		return "watching"
+	case StateLost:
+		return "lost"
+	case StateGivenUp:
+		return "given-up"
+	}
+	return "unknown"
+}
+
+// Redial GPSD with backoff when the watched connection drops.
+// Filters and the WATCH command are kept and replayed on the new connection.
+// Unset Initial, Max and Multiplier are taken from DefaultBackoff.
+func WithReconnect(b Backoff) Option {
+	if b.Initial <= 0 {
+		b.Initial = DefaultBackoff.Initial
+	}
+	if b.Max <= 0 {
+		b.Max = max(DefaultBackoff.Max, b.Initial)
+	}
+	if b.Multiplier <= 0 {
+		b.Multiplier = DefaultBackoff.Multiplier
+	}
+	return func(s *Session) { s.backoff = &b }
+}
+
+// Call fn on every connection state transition
+func WithStateHook(fn func(ConnState)) Option {
+	return func(s *Session) { s.onState = fn }
+}
+
+// Delay before the given (zero based) redial attempt
+func (b Backoff) delay(attempt int) time.Duration {
+	d := float64(b.Initial)
+	for i := 0; i < attempt && (b.Max <= 0 || d < float64(b.Max)); i++ {
+		d *= max(b.Multiplier, 1)
+	}
+	if b.Max > 0 && d > float64(b.Max) {
+		d = float64(b.Max)
+	}
+	if b.Jitter > 0 {
+		d += d * b.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(d)
+}
+
+// Notify the state hook of a transition
+func (s *Session) setState(state ConnState) {
+	if s.onState != nil {
+		s.onState(state)
+	}
+}
+
+// Redial until connected, closed or out of retries and replay the WATCH command
+func (s *Session) reconnect(ctx context.Context) bool {
+	for attempt := 0; s.backoff.MaxRetries == 0 || attempt < s.backoff.MaxRetries; attempt++ {
+		timer := time.NewTimer(s.backoff.delay(attempt))
+		select {
+		case <-s.quit:
+			timer.Stop()
+			return false
+		case <-ctx.Done():
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+
+		s.setState(StateConnecting)
+		if err := s.connect(ctx); err != nil {
+			if s.isClosed() {
+				return false
+			}
+			s.reportError(err)
+			continue
+		}
+
+		s.mu.Lock()
+		watch := s.watch
+		s.mu.Unlock()
+		if err := s.write(ctx, watch); err != nil {
+			s.reportError(err)
+			continue
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package gopsd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithReconnectFillsUnsetFields(t *testing.T) {
+	tests := []struct {
+		name string
+		in   Backoff
+		want Backoff
+	}{
+		{"zero", Backoff{},
+			Backoff{Initial: DefaultBackoff.Initial, Max: DefaultBackoff.Max, Multiplier: DefaultBackoff.Multiplier}},
+		{"retries only", Backoff{MaxRetries: 5, Jitter: 0.1},
+			Backoff{Initial: DefaultBackoff.Initial, Max: DefaultBackoff.Max, Multiplier: DefaultBackoff.Multiplier, Jitter: 0.1, MaxRetries: 5}},
+		{"initial above default max", Backoff{Initial: time.Minute},
+			Backoff{Initial: time.Minute, Max: time.Minute, Multiplier: DefaultBackoff.Multiplier}},
+		{"complete", Backoff{Initial: time.Millisecond, Max: time.Second, Multiplier: 3, Jitter: 0.5, MaxRetries: 2},
+			Backoff{Initial: time.Millisecond, Max: time.Second, Multiplier: 3, Jitter: 0.5, MaxRetries: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s Session
+			WithReconnect(tt.in)(&s)
+			if *s.backoff != tt.want {
+				t.Fatalf("backoff = %+v, want %+v", *s.backoff, tt.want)
+			}
+			if d := s.backoff.delay(0); d <= 0 {
+				t.Fatalf("first delay = %v, want a pause", d)
+			}
+		})
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	b := Backoff{Initial: 100 * time.Millisecond, Max: time.Second, Multiplier: 2}
+	for attempt, want := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		if d := b.delay(attempt); d != want*time.Millisecond {
+			t.Errorf("delay(%d) = %v, want %v", attempt, d, want*time.Millisecond)
+		}
+	}
+
+	b.Jitter = 0.2
+	for range 100 {
+		if d := b.delay(0); d < 80*time.Millisecond || d > 120*time.Millisecond {
+			t.Fatalf("jittered delay %v outside 80-120ms", d)
+		}
+	}
+}
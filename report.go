@@ -0,0 +1,270 @@
+package gopsd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Decoding support for a report class
+type reportType struct {
+	class  string              // Class name as sent by GPSD
+	pooled bool                // Stream class recycled under WithPooledReports
+	pool   sync.Pool           // Recycled reports
+	reset  func(Report)        // Zero a recycled report
+	clone  func(Report) Report // Deep copy of a report
+}
+
+// Report classes decoded into structs, AIS is dispatched on its type field
+var reportTypes = map[string]*reportType{
+	"VERSION":  newReportType[VERSION](false),
+	"TPV":      newReportType[TPV](true),
+	"SKY":      newReportType[SKY](true),
+	"GST":      newReportType[GST](true),
+	"ATT":      newReportType[ATT](true),
+	"IMU":      newReportType[IMU](true),
+	"TOFF":     newReportType[TOFF](true),
+	"PPS":      newReportType[PPS](true),
+	"OSC":      newReportType[OSC](true),
+	"DEVICES":  newReportType[DEVICES](false),
+	"DEVICE":   newReportType[DEVICE](false),
+	"WATCH":    newReportType[WATCH](false),
+	"POLL":     newReportType[POLL](false),
+	"ERROR":    newReportType[ERROR](false),
+	"RAW":      newReportType[RAW](true),
+	"SUBFRAME": newReportType[SUBFRAME](true),
+	"RTCM2":    newReportType[RTCM2](true),
+	"RTCM3":    newReportType[RTCM3](true),
+}
+
+func newReportType[T any, PT interface {
+	*T
+	Report
+}](pooled bool) *reportType {
+	var zero T
+	rt := &reportType{
+		class:  PT(&zero).ReportClass(),
+		pooled: pooled,
+		reset:  func(r Report) { *r.(PT) = *new(T) },
+		clone: func(r Report) Report {
+			c := *r.(PT)
+			if shared, ok := any(&c).(sharedCopier); ok {
+				shared.copyShared()
+			}
+			return PT(&c)
+		},
+	}
+	rt.pool.New = func() interface{} { return PT(new(T)) }
+	return rt
+}
+
+// Reports holding slices or pointers, which a clone must not share
+type sharedCopier interface {
+	copyShared()
+}
+
+func (r *VERSION) copyShared() { r.Remote = clonePtr(r.Remote) }
+func (r *PPS) copyShared()     { r.QErr = clonePtr(r.QErr) }
+func (r *RAW) copyShared()     { r.RawData = slices.Clone(r.RawData) }
+func (r *RTCM3) copyShared()   { r.Satellites = slices.Clone(r.Satellites) }
+
+func (r *SKY) copyShared() {
+	r.Satellites = slices.Clone(r.Satellites)
+}
+
+func (r *SUBFRAME) copyShared() {
+	for _, raw := range []*json.RawMessage{&r.Ephem1, &r.Ephem2, &r.Ephem3, &r.Almanac, &r.Iono, &r.Health, &r.Health2, &r.ERD} {
+		*raw = slices.Clone(*raw)
+	}
+}
+
+func (r *RTCM2) copyShared() {
+	r.Satellites = slices.Clone(r.Satellites)
+	r.Data = slices.Clone(r.Data)
+}
+
+func (r *DEVICES) copyShared() {
+	r.Devices = slices.Clone(r.Devices)
+	r.Remote = clonePtr(r.Remote)
+}
+
+func (r *WATCH) copyShared() {
+	for _, flag := range []**bool{&r.Enable, &r.JSON, &r.NMEA, &r.Scaled, &r.Split24, &r.PPS, &r.Timing} {
+		*flag = clonePtr(*flag)
+	}
+	r.Raw = clonePtr(r.Raw)
+	r.Device = clonePtr(r.Device)
+	r.Remote = clonePtr(r.Remote)
+}
+
+func (r *POLL) copyShared() {
+	r.TPV = slices.Clone(r.TPV)
+	r.Sky = slices.Clone(r.Sky)
+	for i := range r.Sky {
+		r.Sky[i].copyShared()
+	}
+}
+
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	c := *p
+	return &c
+}
+
+// Fresh or recycled empty report
+func (rt *reportType) get(pooled bool) Report {
+	if pooled && rt.pooled {
+		return rt.pool.Get().(Report)
+	}
+	return rt.pool.New().(Report)
+}
+
+// Recycle a report once every filter has seen it
+func (rt *reportType) put(r Report, pooled bool) {
+	if pooled && rt.pooled {
+		rt.reset(r)
+		rt.pool.Put(r)
+	}
+}
+
+// Register fn for every report of type T, the class is derived from T
+func On[T Report](s *Session, fn func(*T)) *Handle {
+	var zero T
+	return s.AddFilter(zero.ReportClass(), func(r interface{}) {
+		if report, ok := r.(*T); ok {
+			fn(report)
+		}
+	})
+}
+
+func (VERSION) ReportClass() string   { return "VERSION" }
+func (TPV) ReportClass() string       { return "TPV" }
+func (SKY) ReportClass() string       { return "SKY" }
+func (GST) ReportClass() string       { return "GST" }
+func (ATT) ReportClass() string       { return "ATT" }
+func (IMU) ReportClass() string       { return "IMU" }
+func (TOFF) ReportClass() string      { return "TOFF" }
+func (PPS) ReportClass() string       { return "PPS" }
+func (OSC) ReportClass() string       { return "OSC" }
+func (DEVICES) ReportClass() string   { return "DEVICES" }
+func (DEVICE) ReportClass() string    { return "DEVICE" }
+func (WATCH) ReportClass() string     { return "WATCH" }
+func (POLL) ReportClass() string      { return "POLL" }
+func (ERROR) ReportClass() string     { return "ERROR" }
+func (RAW) ReportClass() string       { return "RAW" }
+func (SUBFRAME) ReportClass() string  { return "SUBFRAME" }
+func (RTCM2) ReportClass() string     { return "RTCM2" }
+func (RTCM3) ReportClass() string     { return "RTCM3" }
+func (RawReport) ReportClass() string { return UnknownClass }
+
+// ERROR responses are returned as errors by request methods
+func (e *ERROR) Error() string {
+	return "GPSD error: " + e.Message
+}
+
+func (m Mode) String() string {
+	switch m {
+	case NoValue:
+		return "no value"
+	case NoFix:
+		return "no fix"
+	case Mode2D:
+		return "2D"
+	case Mode3D:
+		return "3D"
+	}
+	return "Mode(" + strconv.Itoa(int(m)) + ")"
+}
+
+func (f FixStatus) String() string {
+	switch f {
+	case StatusUnknown:
+		return "unknown"
+	case StatusGPS:
+		return "GPS"
+	case StatusDGPS:
+		return "DGPS"
+	case StatusRTKFixed:
+		return "RTK fixed"
+	case StatusRTKFloat:
+		return "RTK float"
+	case StatusDR:
+		return "dead reckoning"
+	case StatusGNSSDR:
+		return "GNSS+DR"
+	case StatusTime:
+		return "time only"
+	case StatusSimulated:
+		return "simulated"
+	case StatusPY:
+		return "P(Y)"
+	}
+	return "FixStatus(" + strconv.Itoa(int(f)) + ")"
+}
+
+func (a AntennaStatus) String() string {
+	switch a {
+	case AntennaUnknown:
+		return "unknown"
+	case AntennaOK:
+		return "OK"
+	case AntennaOpen:
+		return "open"
+	case AntennaShort:
+		return "short"
+	}
+	return "AntennaStatus(" + strconv.Itoa(int(a)) + ")"
+}
+
+func (g GNSS) String() string {
+	switch g {
+	case GNSSGPS:
+		return "GPS"
+	case GNSSSBAS:
+		return "SBAS"
+	case GNSSGalileo:
+		return "Galileo"
+	case GNSSBeiDou:
+		return "BeiDou"
+	case GNSSIMES:
+		return "IMES"
+	case GNSSQZSS:
+		return "QZSS"
+	case GNSSGLONASS:
+		return "GLONASS"
+	case GNSSNavIC:
+		return "NavIC"
+	}
+	return "GNSS(" + strconv.Itoa(int(g)) + ")"
+}
+
+// Accept an ISO 8601 string or a number of seconds, 0 being the zero time
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if len(data) == 0 || data[0] == '"' || string(data) == "null" {
+		return t.Time.UnmarshalJSON(data)
+	}
+	seconds, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %s", data)
+	}
+	t.Time = time.Time{}
+	if seconds != 0 {
+		whole, frac := math.Modf(seconds)
+		t.Time = time.Unix(int64(whole), int64(frac*1e9)).UTC()
+	}
+	return nil
+}
+
+// Encode the zero time as 0 like GPSD does
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("0"), nil
+	}
+	return t.Time.MarshalJSON()
+}
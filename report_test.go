@@ -0,0 +1,96 @@
+package gopsd
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+var benchLines = []struct {
+	class string
+	line  string
+}{
+	{"TPV", `{"class":"TPV","device":"/dev/ttyACM0","mode":3,"time":"2024-01-01T00:00:01.000Z","ept":0.005,"lat":46.498293369,"lon":7.567411672,"altHAE":1343.127,"altMSL":1295.967,"alt":1295.967,"epx":3.674,"epy":4.111,"epv":6.85,"track":10.3788,"magtrack":12.2217,"magvar":1.8,"speed":0.091,"climb":-0.085,"eps":0.82,"epc":13.7,"ecefx":4350525.96,"ecefy":579006.55,"ecefz":4604014.47,"ecefvx":-0.06,"ecefvy":0.04,"ecefvz":-0.05,"ecefpAcc":5.1,"ecefvAcc":0.67,"geoidSep":47.16,"eph":4.52,"sep":8.12}`},
+	{"SKY", `{"class":"SKY","device":"/dev/ttyACM0","time":"2024-01-01T00:00:01.000Z","xdop":0.54,"ydop":0.77,"vdop":0.85,"tdop":1.0,"hdop":0.94,"gdop":1.57,"pdop":1.27,"nSat":8,"uSat":6,"satellites":[{"PRN":5,"el":31.0,"az":86.0,"ss":45.0,"used":true,"gnssid":0,"svid":5,"health":1},{"PRN":10,"el":10.0,"az":278.0,"ss":40.0,"used":false,"gnssid":0,"svid":10,"health":1},{"PRN":13,"el":44.0,"az":53.0,"ss":47.0,"used":true,"gnssid":0,"svid":13,"health":1},{"PRN":15,"el":80.0,"az":68.0,"ss":48.0,"used":true,"gnssid":0,"svid":15,"health":1},{"PRN":65,"el":21.0,"az":30.0,"ss":38.0,"used":true,"gnssid":6,"svid":1,"health":1},{"PRN":72,"el":44.0,"az":136.0,"ss":42.0,"used":true,"gnssid":6,"svid":8,"health":1},{"PRN":211,"el":65.0,"az":291.0,"ss":43.0,"used":false,"gnssid":2,"svid":11,"health":1},{"PRN":225,"el":12.0,"az":215.0,"ss":35.0,"used":false,"gnssid":2,"svid":25,"health":1}]}`},
+	{"ATT", `{"class":"ATT","device":"/dev/ttyACM0","time":"2024-01-01T00:00:01.000Z","heading":14223.00,"mag_st":"N","pitch":169.00,"pitch_st":"N","yaw":0.00,"yaw_st":"N","roll":-43.00,"roll_st":"N","dip":0.00,"mag_len":0.000,"mag_x":0.000,"mag_y":0.000,"mag_z":0.000,"acc_len":0.000,"acc_x":0.000,"acc_y":0.000,"acc_z":0.000,"gyro_x":0.000,"gyro_y":0.000,"depth":0.000,"temp":0.000}`},
+}
+
+// Session dispatching to a single no-op filter, without a connection
+func benchSession(class string, pooled bool) *Session {
+	s := &Session{decoder: defaultDecoder, maxLine: defaultMaxLineSize, pooled: pooled, filters: map[string][]*filterEntry{}}
+	s.AddFilter(class, func(interface{}) {})
+	return s
+}
+
+func BenchmarkHandleLine(b *testing.B) {
+	for _, bl := range benchLines {
+		line := []byte(bl.line)
+		for _, pooled := range []bool{false, true} {
+			name := bl.class + "/unpooled"
+			if pooled {
+				name = bl.class + "/pooled"
+			}
+			b.Run(name, func(b *testing.B) {
+				s := benchSession(bl.class, pooled)
+				b.ReportAllocs()
+				b.SetBytes(int64(len(line)))
+				for range b.N {
+					s.handleLine(line)
+				}
+			})
+		}
+	}
+}
+
+// Lines of classes nobody subscribed to are dropped after the class scan
+func BenchmarkHandleLineUnsubscribed(b *testing.B) {
+	for _, bl := range benchLines {
+		line := []byte(bl.line)
+		b.Run(bl.class, func(b *testing.B) {
+			s := benchSession("DEVICE", false)
+			b.ReportAllocs()
+			for range b.N {
+				s.handleLine(line)
+			}
+		})
+	}
+}
+
+func BenchmarkPeekClass(b *testing.B) {
+	for _, bl := range benchLines {
+		line := []byte(bl.line)
+		b.Run(bl.class, func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				peekClass(line)
+			}
+		})
+	}
+}
+
+func TestTimestampUnmarshal(t *testing.T) {
+	tests := []struct {
+		json string
+		want time.Time
+		err  bool
+	}{
+		{`"2024-01-01T00:00:01.500Z"`, time.Date(2024, 1, 1, 0, 0, 1, 500e6, time.UTC), false},
+		{`0`, time.Time{}, false},
+		{`0.0`, time.Time{}, false},
+		{`1700000000.25`, time.Unix(1700000000, 250e6).UTC(), false},
+		{`null`, time.Time{}, false},
+		{`"yesterday"`, time.Time{}, true},
+		{`true`, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		var device DEVICE
+		err := json.Unmarshal([]byte(`{"class":"DEVICE","activated":`+tt.json+`}`), &device)
+		if (err != nil) != tt.err {
+			t.Errorf("activated %s: error %v, want error %v", tt.json, err, tt.err)
+			continue
+		}
+		if !tt.err && !device.Activated.Equal(tt.want) {
+			t.Errorf("activated %s = %v, want %v", tt.json, device.Activated, tt.want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package gopsd
+
+import "context"
+
+// Fetch the latest fixes from every active device with ?POLL, alongside
+// or instead of an active watch. Devices are only pollable once activated
+// by a WATCH, so a session that has not watched yet first enables watching
+// without JSON streaming.
+func (s *Session) Poll(ctx context.Context) (*POLL, error) {
+	s.mu.Lock()
+	watching := s.watch != nil
+	s.mu.Unlock()
+
+	if !watching {
+		enable := true
+		if _, err := s.WatchWith(ctx, WATCH{Enable: &enable}); err != nil {
+			return nil, err
+		}
+	}
+
+	reply, err := s.request(ctx, []byte("?POLL;"), "POLL", nil)
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*POLL), nil
+}
+
+// Send a command and wait for the first report of class accepted by match
+// (nil accepts any). Requests are serialised so replies correlate with
+// their command, and a GPSD ERROR received meanwhile is returned as the error.
+func (s *Session) request(ctx context.Context, command []byte, class string, match func(Report) bool) (Report, error) {
+	s.reqMu.Lock()
+	defer s.reqMu.Unlock()
+
+	replies := make(chan Report, 1)
+	accept := func(r interface{}) {
+		select {
+		case replies <- r.(Report):
+		default:
+		}
+	}
+	defer s.AddFilter(class, func(r interface{}) {
+		if match == nil || match(r.(Report)) {
+			accept(r)
+		}
+	}).Unsubscribe()
+	defer s.AddFilter("ERROR", accept).Unsubscribe()
+
+	if err := s.write(ctx, command); err != nil {
+		return nil, err
+	}
+	s.startReader(context.Background())
+
+	select {
+	case reply := <-replies:
+		if e, ok := reply.(*ERROR); ok {
+			return nil, e
+		}
+		return reply, nil
+	case <-s.done:
+		return nil, s.Err()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,193 @@
+package gopsd
+
+import "bytes"
+
+var classPrefix = []byte(`{"class":"`)
+
+// Extract the class of a report without decoding it. GPSD always sends
+// the class first, other layouts fall back to walking the top level keys.
+func peekClass(line []byte) (string, bool) {
+	if bytes.HasPrefix(line, classPrefix) {
+		rest := line[len(classPrefix):]
+		if end := bytes.IndexByte(rest, '"'); end >= 0 && bytes.IndexByte(rest[:end], '\\') < 0 {
+			return internClass(rest[:end]), true
+		}
+	}
+
+	var class string
+	found := false
+	eachMember(line, func(key, value []byte) bool {
+		if string(key) != "class" {
+			return true
+		}
+		if len(value) < 2 || value[0] != '"' || bytes.IndexByte(value, '\\') >= 0 {
+			return false
+		}
+		class, found = internClass(value[1:len(value)-1]), true
+		return false
+	})
+	return class, found
+}
+
+// Return the class as a string, reusing the constant for known classes
+func internClass(name []byte) string {
+	if rt, ok := reportTypes[string(name)]; ok {
+		return rt.class
+	}
+	if string(name) == "AIS" {
+		return "AIS"
+	}
+	return string(name)
+}
+
+// Call fn with the raw key (unquoted) and raw value of each top level
+// member of a JSON object until fn returns false. Reports whether the
+// object was well formed up to where the walk stopped.
+func eachMember(obj []byte, fn func(key, value []byte) bool) bool {
+	i := skipSpace(obj, 0)
+	if i >= len(obj) || obj[i] != '{' {
+		return false
+	}
+	i = skipSpace(obj, i+1)
+	if i < len(obj) && obj[i] == '}' {
+		return true
+	}
+
+	for i < len(obj) {
+		keyEnd := skipString(obj, i)
+		if keyEnd < 0 {
+			return false
+		}
+		key := obj[i+1 : keyEnd-1]
+
+		i = skipSpace(obj, keyEnd)
+		if i >= len(obj) || obj[i] != ':' {
+			return false
+		}
+		i = skipSpace(obj, i+1)
+		valueEnd := skipValue(obj, i)
+		if valueEnd < 0 {
+			return false
+		}
+		if !fn(key, obj[i:valueEnd]) {
+			return true
+		}
+
+		i = skipSpace(obj, valueEnd)
+		if i >= len(obj) {
+			return false
+		}
+		switch obj[i] {
+		case ',':
+			i = skipSpace(obj, i+1)
+		case '}':
+			return true
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// Call fn with the raw value of each element of a JSON array until fn
+// returns false. Reports whether the array was well formed up to where
+// the walk stopped.
+func eachElement(arr []byte, fn func(value []byte) bool) bool {
+	i := skipSpace(arr, 0)
+	if i >= len(arr) || arr[i] != '[' {
+		return false
+	}
+	i = skipSpace(arr, i+1)
+	if i < len(arr) && arr[i] == ']' {
+		return true
+	}
+
+	for i < len(arr) {
+		valueEnd := skipValue(arr, i)
+		if valueEnd < 0 {
+			return false
+		}
+		if !fn(arr[i:valueEnd]) {
+			return true
+		}
+
+		i = skipSpace(arr, valueEnd)
+		if i >= len(arr) {
+			return false
+		}
+		switch arr[i] {
+		case ',':
+			i = skipSpace(arr, i+1)
+		case ']':
+			return true
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// Index of the first non-whitespace byte at or after i
+func skipSpace(b []byte, i int) int {
+	for i < len(b) && (b[i] == ' ' || b[i] == '\t' || b[i] == '\r' || b[i] == '\n') {
+		i++
+	}
+	return i
+}
+
+// Index just past the string starting at i, -1 if malformed
+func skipString(b []byte, i int) int {
+	if i >= len(b) || b[i] != '"' {
+		return -1
+	}
+	for i++; i < len(b); i++ {
+		switch b[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// Index just past the value starting at i, -1 if malformed
+func skipValue(b []byte, i int) int {
+	if i >= len(b) {
+		return -1
+	}
+	switch b[i] {
+	case '"':
+		return skipString(b, i)
+	case '{', '[':
+		depth := 0
+		for ; i < len(b); i++ {
+			switch b[i] {
+			case '"':
+				end := skipString(b, i)
+				if end < 0 {
+					return -1
+				}
+				i = end - 1
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return i + 1
+				}
+			}
+		}
+		return -1
+	default:
+		start := i
+		for i < len(b) && b[i] != ',' && b[i] != '}' && b[i] != ']' &&
+			b[i] != ' ' && b[i] != '\t' && b[i] != '\r' && b[i] != '\n' {
+			i++
+		}
+		if i == start {
+			return -1
+		}
+		return i
+	}
+}
@@ -0,0 +1,80 @@
+package gopsd
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPeekClass(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		class string
+		ok    bool
+	}{
+		{"class first", `{"class":"TPV","mode":3}`, "TPV", true},
+		{"class not first", `{"device":"/dev/ttyACM0","class":"SKY"}`, "SKY", true},
+		{"whitespace", ` { "class" : "ATT" } `, "ATT", true},
+		{"unknown class", `{"class":"FOO"}`, "FOO", true},
+		{"escaped class", `{"class":"T\"PV"}`, "", false},
+		{"escaped string before class", `{"device":"a\"b,\"class\":\"X","class":"GST"}`, "GST", true},
+		{"nested class", `{"tpv":[{"class":"TPV"}],"sky":{"class":"SKY"},"class":"POLL"}`, "POLL", true},
+		{"only nested class", `{"tpv":[{"class":"TPV"}]}`, "", false},
+		{"class not a string", `{"device":"x","class":3}`, "", false},
+		{"truncated before class", `{"device":"x","cla`, "", false},
+		{"truncated after class", `{"class":"TPV","mode":`, "TPV", true},
+		{"empty object", `{}`, "", false},
+		{"array", `[{"class":"TPV"}]`, "", false},
+		{"empty", ``, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			class, ok := peekClass([]byte(tt.line))
+			if class != tt.class || ok != tt.ok {
+				t.Errorf("peekClass(%s) = %q, %v, want %q, %v", tt.line, class, ok, tt.class, tt.ok)
+			}
+		})
+	}
+}
+
+func TestEachMember(t *testing.T) {
+	tests := []struct {
+		name   string
+		obj    string
+		stop   string // Key at which the walk stops
+		keys   []string
+		values []string
+		ok     bool
+	}{
+		{"empty", `{}`, "", nil, nil, true},
+		{"scalars", `{"a":1,"b":true,"c":null,"d":-1.5e3}`, "",
+			[]string{"a", "b", "c", "d"}, []string{"1", "true", "null", "-1.5e3"}, true},
+		{"escaped strings", `{"a\"b":"x\"}y","c":"\\"}`, "",
+			[]string{`a\"b`, "c"}, []string{`"x\"}y"`, `"\\"`}, true},
+		{"nested", `{"a":{"b":[1,{"c":"]"}]},"d":[]}`, "",
+			[]string{"a", "d"}, []string{`{"b":[1,{"c":"]"}]}`, "[]"}, true},
+		{"whitespace", "{ \"a\" :\t1 ,\r\n\"b\": [ 2 ] }", "",
+			[]string{"a", "b"}, []string{"1", "[ 2 ]"}, true},
+		{"stop early", `{"a":1,"b":2,"c":`, "b", []string{"a", "b"}, []string{"1", "2"}, true},
+		{"truncated value", `{"a":1,"b":`, "", []string{"a"}, []string{"1"}, false},
+		{"truncated string", `{"a":"abc`, "", nil, nil, false},
+		{"truncated nested", `{"a":{"b":[1,2}`, "", nil, nil, false},
+		{"missing colon", `{"a" 1}`, "", nil, nil, false},
+		{"missing comma", `{"a":1 "b":2}`, "", []string{"a"}, []string{"1"}, false},
+		{"not an object", `[1,2]`, "", nil, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keys, values []string
+			ok := eachMember([]byte(tt.obj), func(key, value []byte) bool {
+				keys = append(keys, string(key))
+				values = append(values, string(value))
+				return string(key) != tt.stop
+			})
+			if ok != tt.ok || !slices.Equal(keys, tt.keys) || !slices.Equal(values, tt.values) {
+				t.Errorf("eachMember(%s) = %q %q %v, want %q %q %v",
+					tt.obj, keys, values, ok, tt.keys, tt.values, tt.ok)
+			}
+		})
+	}
+}
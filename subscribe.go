@@ -0,0 +1,109 @@
+package gopsd
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Overflow policy constants
+const (
+	Block      Overflow = iota // Wait for the consumer, stalling dispatch
+	DropOldest                 // Discard the oldest buffered report
+	DropNewest                 // Discard the incoming report
+)
+
+type Overflow byte // Behaviour of a full subscription channel
+
+// Buffered channel of reports fed by the watcher
+type Subscription[T any] struct {
+	C <-chan T // Reports in arrival order
+
+	ch      chan T        // Send side of C
+	policy  Overflow      // Full channel behaviour
+	dropped atomic.Uint64 // Reports discarded by the policy
+	handle  *Handle       // Filter feeding the channel
+	mu      sync.Mutex    // Serialises deliveries and closing
+	closed  bool          // Set once C has been closed
+	done    chan struct{} // Closed by Unsubscribe to release a blocked delivery
+	once    sync.Once     // Guards Unsubscribe
+}
+
+// Subscribe to reports of type T through a channel holding size reports.
+// Reports recycled by WithPooledReports are copied before being queued.
+func Subscribe[T Report](s *Session, size int, policy Overflow) *Subscription[*T] {
+	sub := newSubscription[*T](size, policy)
+	sub.handle = On(s, func(report *T) {
+		sub.deliver(any(s.retain(any(report).(Report))).(*T))
+	})
+	return sub
+}
+
+// Subscribe to every report accepted by match, regardless of class
+func SubscribeFunc(s *Session, size int, policy Overflow, match func(Report) bool) *Subscription[Report] {
+	sub := newSubscription[Report](size, policy)
+	sub.handle = s.AddFilter(AllClasses, func(r interface{}) {
+		if report, ok := r.(Report); ok && match(report) {
+			sub.deliver(s.retain(report))
+		}
+	})
+	return sub
+}
+
+// Number of reports discarded because the channel was full
+func (sub *Subscription[T]) Dropped() uint64 {
+	return sub.dropped.Load()
+}
+
+// Stop receiving reports and close C, buffered reports remain readable
+func (sub *Subscription[T]) Unsubscribe() {
+	sub.once.Do(func() {
+		sub.handle.Unsubscribe()
+		close(sub.done)
+
+		sub.mu.Lock()
+		defer sub.mu.Unlock()
+		sub.closed = true
+		close(sub.ch)
+	})
+}
+
+func newSubscription[T any](size int, policy Overflow) *Subscription[T] {
+	ch := make(chan T, max(size, 1))
+	return &Subscription[T]{C: ch, ch: ch, policy: policy, done: make(chan struct{})}
+}
+
+// Queue a report according to the overflow policy
+func (sub *Subscription[T]) deliver(report T) {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	if sub.closed {
+		return
+	}
+
+	switch sub.policy {
+	case Block:
+		select {
+		case sub.ch <- report:
+		case <-sub.done:
+		}
+	case DropNewest:
+		select {
+		case sub.ch <- report:
+		default:
+			sub.dropped.Add(1)
+		}
+	case DropOldest:
+		for {
+			select {
+			case sub.ch <- report:
+				return
+			default:
+			}
+			select {
+			case <-sub.ch:
+				sub.dropped.Add(1)
+			default:
+			}
+		}
+	}
+}
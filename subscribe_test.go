@@ -0,0 +1,26 @@
+package gopsd
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSubscribeWithPooledReports(t *testing.T) {
+	s := &Session{decoder: defaultDecoder, maxLine: defaultMaxLineSize, pooled: true, filters: map[string][]*filterEntry{}}
+	fixes := Subscribe[TPV](s, 10, Block)
+	all := SubscribeFunc(s, 10, Block, func(r Report) bool { return r.ReportClass() == "TPV" })
+
+	for i := 1; i <= 3; i++ {
+		s.handleLine(fmt.Appendf(nil, `{"class":"TPV","device":"/dev/ttyACM0","mode":3,"lat":%d,"lon":2}`, i))
+	}
+
+	for i := 1; i <= 3; i++ {
+		tpv := <-fixes.C
+		if tpv.Lat != float64(i) || tpv.Mode != Mode3D {
+			t.Errorf("Subscribe report %d: lat %v mode %s, want lat %d mode 3D", i, tpv.Lat, tpv.Mode, i)
+		}
+		if tpv := (<-all.C).(*TPV); tpv.Lat != float64(i) || tpv.Mode != Mode3D {
+			t.Errorf("SubscribeFunc report %d: lat %v mode %s, want lat %d mode 3D", i, tpv.Lat, tpv.Mode, i)
+		}
+	}
+}